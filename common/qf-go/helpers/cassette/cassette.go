@@ -0,0 +1,293 @@
+// Package cassette records HTTP exchanges to a JSON file and replays them in tests, so a
+// sync against Odoo JSON-RPC and the Shopify Admin API can be turned into a regression
+// test. Credentials and personal data are scrubbed before anything is written.
+package cassette
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type Mode int
+
+const (
+	Replay Mode = iota
+	Record
+)
+
+// Interaction is a recorded request and its response. Only the URL path is kept, so
+// cassettes replay against any Odoo or Shopify domain.
+type Interaction struct {
+	Method       string `json:"method"`
+	Path         string `json:"path"`
+	Request      any    `json:"request,omitempty"`
+	Status       int    `json:"status"`
+	Response     any    `json:"response,omitempty"`
+	ResponseText string `json:"responseText,omitempty"` // Response body when not JSON
+}
+
+// Values of these JSON keys are replaced by a hash in requests and responses. The same
+// value is then replaced wherever it appears, e.g. a Shopify customer name sent to Odoo.
+var DefaultScrubKeys = []string{
+	"email", "emailAddress", "phone", "phoneNumber", "mobile", "login", "password",
+	"displayName", "firstName", "lastName", "recipient",
+	"address1", "address2", "street", "street2", "zip",
+}
+
+// Keys also scrubbed in objects that look like an address.
+var addressScrubKeys = []string{"name", "company", "companyName"}
+
+const scrubbedPrefix = "scrubbed-"
+
+// Cassette is an http.RoundTripper that records exchanges through Transport, or replays
+// them from the interactions loaded from Path. During replay each request is answered by
+// the first unused interaction with the same method, path and scrubbed body, so calls
+// made in a different order (e.g. ranging over a map) still replay.
+type Cassette struct {
+	Path      string
+	Mode      Mode
+	Transport http.RoundTripper // Used while recording, http.DefaultTransport when nil
+	ScrubKeys []string          // DefaultScrubKeys when nil
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+	scrubbed     map[string]string
+}
+
+// New returns an empty cassette recording to path.
+func New(path string) *Cassette {
+	return &Cassette{Path: path, Mode: Record, scrubbed: map[string]string{}}
+}
+
+// Load reads the cassette at path for replay.
+func Load(path string) (*Cassette, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading cassette %v:\n>>> %w", path, err)
+	}
+	file := struct {
+		Interactions []Interaction `json:"interactions"`
+	}{}
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("invalid cassette %v:\n>>> %w", path, err)
+	}
+	return &Cassette{
+		Path:         path,
+		Mode:         Replay,
+		interactions: file.Interactions,
+		used:         make([]bool, len(file.Interactions)),
+		scrubbed:     map[string]string{},
+	}, nil
+}
+
+// Open replays the cassette at path in the test, or records it again against the real
+// services when CASSETTE_RECORD is set, saving it at the end of the test.
+func Open(t testing.TB, path string) *Cassette {
+	t.Helper()
+	if os.Getenv("CASSETTE_RECORD") != "" {
+		c := New(path)
+		t.Cleanup(func() {
+			if err := c.Save(); err != nil {
+				t.Errorf("error saving cassette: %v", err)
+			}
+		})
+		return c
+	}
+	c, err := Load(path)
+	if err != nil {
+		t.Fatalf("%v\n(run the test with CASSETTE_RECORD=1 to record it)", err)
+	}
+	return c
+}
+
+// Interactions returns a copy of the recorded interactions.
+func (c *Cassette) Interactions() []Interaction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.interactions)
+}
+
+// Save writes the recorded interactions to Path.
+func (c *Cassette) Save() error {
+	content, err := json.MarshalIndent(map[string]any{"interactions": c.Interactions()}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not serialize cassette:\n>>> %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.Path), 0o755); err != nil {
+		return fmt.Errorf("error creating cassette directory:\n>>> %w", err)
+	}
+	if err := os.WriteFile(c.Path, append(content, '\n'), 0o644); err != nil {
+		return fmt.Errorf("error writing cassette %v:\n>>> %w", c.Path, err)
+	}
+	return nil
+}
+
+func (c *Cassette) RoundTrip(r *http.Request) (*http.Response, error) {
+	requestBody := []byte{}
+	if r.Body != nil {
+		var err error
+		requestBody, err = io.ReadAll(r.Body)
+		r.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		r.Body = io.NopCloser(bytes.NewReader(requestBody))
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	request, _ := c.scrubBody(requestBody)
+	if c.Mode == Replay {
+		return c.replay(r, request)
+	}
+
+	transport := c.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	response, err := transport.RoundTrip(r)
+	if err != nil {
+		return nil, err
+	}
+	responseBody, err := io.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	response.Body = io.NopCloser(bytes.NewReader(responseBody))
+
+	interaction := Interaction{Method: r.Method, Path: r.URL.Path, Request: request, Status: response.StatusCode}
+	if scrubbed, isJson := c.scrubBody(responseBody); isJson {
+		interaction.Response = scrubbed
+	} else {
+		interaction.ResponseText = string(responseBody)
+	}
+	c.interactions = append(c.interactions, interaction)
+	return response, nil
+}
+
+func (c *Cassette) replay(r *http.Request, request any) (*http.Response, error) {
+	for i, interaction := range c.interactions {
+		if c.used[i] || interaction.Method != r.Method || interaction.Path != r.URL.Path || !reflect.DeepEqual(interaction.Request, request) {
+			continue
+		}
+		c.used[i] = true
+		body := []byte(interaction.ResponseText)
+		if interaction.Response != nil {
+			body, _ = json.Marshal(interaction.Response)
+		}
+		return &http.Response{
+			StatusCode: interaction.Status,
+			Status:     fmt.Sprintf("%d %s", interaction.Status, http.StatusText(interaction.Status)),
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(bytes.NewReader(body)),
+			Request:    r,
+		}, nil
+	}
+	requestJson, _ := json.Marshal(request)
+	return nil, fmt.Errorf("no unused interaction in cassette %v for %v %v with body %s", c.Path, r.Method, r.URL.Path, requestJson)
+}
+
+// scrubBody decodes a JSON body and scrubs it, or returns it as a string.
+func (c *Cassette) scrubBody(body []byte) (any, bool) {
+	if len(body) == 0 {
+		return nil, true
+	}
+	var decoded any
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return string(body), false
+	}
+	if rpc, ok := decoded.(map[string]any); ok {
+		scrubJsonRpc(rpc)
+	}
+	return c.scrub(decoded, false), true
+}
+
+// scrubJsonRpc removes the database credentials from Odoo JSON-RPC requests, and the
+// request ID which is the user ID.
+func scrubJsonRpc(rpc map[string]any) {
+	if _, isRpc := rpc["jsonrpc"]; !isRpc {
+		return
+	}
+	delete(rpc, "id")
+	params, _ := rpc["params"].(map[string]any)
+	args, _ := params["args"].([]any)
+	switch {
+	case params["service"] == "object" && len(args) >= 3:
+		args[0], args[1], args[2] = "<db>", "<uid>", "<password>"
+	case params["service"] == "common" && len(args) >= 3:
+		// login and authenticate
+		args[0], args[1], args[2] = "<db>", "<login>", "<password>"
+	}
+	if params["db"] != nil {
+		// /web/session/authenticate
+		params["db"], params["login"], params["password"] = "<db>", "<login>", "<password>"
+	}
+}
+
+func (c *Cassette) scrub(value any, scrubString bool) any {
+	switch value := value.(type) {
+	case map[string]any:
+		keys := c.ScrubKeys
+		if keys == nil {
+			keys = DefaultScrubKeys
+		}
+		_, isShopifyAddress := value["address1"]
+		_, isOdooAddress := value["street"]
+		scrubbed := make(map[string]any, len(value))
+		// Sorted so values are registered in the same order on every run
+		for _, key := range slices.Sorted(maps.Keys(value)) {
+			scrubKey := slices.Contains(keys, key) || (isShopifyAddress || isOdooAddress) && slices.Contains(addressScrubKeys, key)
+			scrubbed[key] = c.scrub(value[key], scrubKey)
+		}
+		return scrubbed
+	case []any:
+		scrubbed := make([]any, len(value))
+		for i, item := range value {
+			scrubbed[i] = c.scrub(item, scrubString)
+		}
+		return scrubbed
+	case string:
+		return c.scrubString(value, scrubString)
+	}
+	return value
+}
+
+func (c *Cassette) scrubString(value string, scrubValue bool) string {
+	if value == "" || strings.HasPrefix(value, scrubbedPrefix) || strings.HasPrefix(value, "<") {
+		return value
+	}
+	if replacement, found := c.scrubbed[value]; found {
+		return replacement
+	}
+	if scrubValue {
+		hash := sha256.Sum256([]byte(value))
+		replacement := scrubbedPrefix + hex.EncodeToString(hash[:4])
+		if strings.Contains(value, "@") {
+			replacement += "@example.com"
+		}
+		c.scrubbed[value] = replacement
+		return replacement
+	}
+	// Values scrubbed elsewhere, e.g. a name within a longer note
+	for _, known := range slices.Sorted(maps.Keys(c.scrubbed)) {
+		if len(known) >= 5 && strings.Contains(value, known) {
+			value = strings.ReplaceAll(value, known, c.scrubbed[known])
+		}
+	}
+	return value
+}
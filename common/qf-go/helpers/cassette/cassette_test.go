@@ -0,0 +1,82 @@
+package cassette
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"qf/go/helpers"
+	"qf/go/odoo"
+	"qf/go/odoo/odootest"
+	"strings"
+	"testing"
+)
+
+const customerQuery = `query customer($id: ID!) { customer(id: $id) { displayName defaultEmailAddress { emailAddress } } }`
+
+// syncCustomer reads a customer from Shopify and creates it in Odoo.
+func syncCustomer(t *testing.T, shopifyUrl string, client *odoo.Client) (int, error) {
+	t.Helper()
+	response, err := helpers.GraphQLQuery(shopifyUrl, "X-Shopify-Access-Token", "shpat_secret", customerQuery, map[string]any{"id": "gid://shopify/Customer/1001"})
+	if err != nil {
+		t.Fatalf("unexpected GraphQL error: %v", err)
+	}
+	customer := helpers.Traverse[map[string]any](response, []any{"data", "customer"}, nil)
+	email := helpers.Traverse[string](customer, []any{"defaultEmailAddress", "emailAddress"}, "")
+	return client.Create("res.partner", map[string]any{
+		"name":    customer["displayName"],
+		"email":   email,
+		"comment": "Created for " + customer["displayName"].(string),
+	}, nil)
+}
+
+func TestCassette_RecordReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "customer.json")
+	shopify := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"customer": map[string]any{
+			"displayName":         "Jane Doe",
+			"defaultEmailAddress": map[string]any{"emailAddress": "jane@example.com"},
+		}}})
+	}))
+	odooServer := odootest.NewServer(t)
+
+	recorder := New(path)
+	reset := helpers.TempSet[http.RoundTripper](&helpers.GraphQLTransport, recorder)
+	recordClient, _ := odoo.NewClient(odoo.Config{URL: odooServer.URL, DB: "prod", UserID: 7, Password: "odoo_secret", Transport: recorder})
+	recordedId, err := syncCustomer(t, shopify.URL+"/admin/api/2025-04/graphql.json", recordClient)
+	reset()
+	if err != nil {
+		t.Fatalf("unexpected error while recording: %v", err)
+	}
+	if err := recorder.Save(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	shopify.Close()
+	odooServer.Close()
+
+	content, _ := os.ReadFile(path)
+	for _, secret := range []string{"shpat_secret", "odoo_secret", "prod", "Jane Doe", "jane@example.com"} {
+		if strings.Contains(string(content), secret) {
+			t.Fatalf("cassette contains %q:\n%s", secret, content)
+		}
+	}
+
+	player, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer helpers.TempSet[http.RoundTripper](&helpers.GraphQLTransport, player)()
+	replayClient, _ := odoo.NewClient(odoo.Config{URL: "https://odoo.test", DB: "test", UserID: 2, Password: "pwd", Transport: player})
+	replayedId, err := syncCustomer(t, "https://shop.test/admin/api/2025-04/graphql.json", replayClient)
+	if err != nil {
+		t.Fatalf("unexpected error while replaying: %v", err)
+	}
+	if replayedId != recordedId {
+		t.Fatalf("expected replayed ID %v, got %v", recordedId, replayedId)
+	}
+
+	if _, err := replayClient.Create("res.partner", map[string]any{"name": "Not recorded"}, nil); err == nil || !strings.Contains(err.Error(), "no unused interaction") {
+		t.Fatalf("expected error for a request missing from the cassette, got %v", err)
+	}
+}
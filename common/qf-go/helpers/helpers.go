@@ -17,6 +17,10 @@ import (
 	"golang.org/x/text/unicode/norm"
 )
 
+// GraphQLTransport is the transport used by GraphQLQuery, http.DefaultTransport when nil.
+// Tests replace it to record or replay Shopify traffic.
+var GraphQLTransport http.RoundTripper
+
 func GraphQLQuery(url string, authHeader string, authToken string, query string, variables map[string]any) (any, error) {
 	requestBody, err := json.Marshal(map[string]any{
 		"query":     query,
@@ -35,7 +39,7 @@ func GraphQLQuery(url string, authHeader string, authToken string, query string,
 		request.Header.Add(authHeader, authToken)
 	}
 
-	client := &http.Client{Timeout: 30 * time.Second}
+	client := &http.Client{Timeout: 30 * time.Second, Transport: GraphQLTransport}
 	response, err := client.Do(request)
 	if err != nil {
 		return nil, fmt.Errorf("error requesting GraphQL query:\n>>> %w", err)
@@ -51,6 +51,17 @@ func CheckEnvMiddleware(function NetlifyFunction) NetlifyFunction {
 	}
 }
 
+// WebhookTopicHeader carries the topic of a Shopify webhook, e.g. orders/updated, in the
+// RabbitMQ message published by shopify-webhook, and in the requests the consumer of the
+// queue forwards it with to the process functions.
+const WebhookTopicHeader = "x-shopify-topic"
+
+// WebhookTopic returns the topic of the webhook a process function received, empty when
+// it was not forwarded.
+func WebhookTopic(request events.APIGatewayProxyRequest) string {
+	return request.Headers[WebhookTopicHeader]
+}
+
 func NetlifyResponseWithHeaders(statusCode int, body string, headers map[string]string) (*events.APIGatewayProxyResponse, error) {
 	return &events.APIGatewayProxyResponse{
 		StatusCode: statusCode,
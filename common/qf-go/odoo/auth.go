@@ -0,0 +1,141 @@
+package odoo
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// AuthMethod selects how calls are authenticated.
+type AuthMethod string
+
+const (
+	// AuthPassword sends the user ID and the password, or API key, with every call to
+	// /jsonrpc. The user ID is discovered through common.authenticate when only the
+	// login is configured.
+	AuthPassword AuthMethod = ""
+	// AuthSession authenticates once through /web/session/authenticate, then sends
+	// calls to /web/dataset/call_kw with the session cookie only. Odoo does not accept
+	// API keys for sessions, the password of the user is required.
+	AuthSession AuthMethod = "session"
+)
+
+const ExceptionSessionExpired = "odoo.http.SessionExpiredException"
+
+// ErrAuthentication is returned when Odoo rejects the login and password.
+var ErrAuthentication = errors.New("invalid Odoo login or password")
+
+// authState is shared by the copies of a client made by the With* methods, so the
+// authentication happens once.
+type authState struct {
+	sync.Mutex
+	uid           int
+	authenticated bool // Session cookie received
+}
+
+// UserID returns the ID of the user calls are made as, from Config.UserID when set or
+// else discovered once through common.authenticate, or the session.
+func (c *Client) UserID() (int, error) {
+	if c.config.UserID != 0 {
+		return c.config.UserID, nil
+	}
+	c.auth.Lock()
+	defer c.auth.Unlock()
+	if c.auth.uid != 0 {
+		return c.auth.uid, nil
+	}
+	if c.config.Auth == AuthSession {
+		if err := c.authenticateSession(); err != nil {
+			return 0, err
+		}
+		return c.auth.uid, nil
+	}
+
+	result, err := c.withRetry(retryRead, func() (any, error) {
+		return c.jsonRpc("common", "authenticate", []any{c.config.DB, c.config.Login, c.config.Password, map[string]any{}})
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error authenticating %v on Odoo:\n>>> %w", c.config.Login, err)
+	}
+	uid := recordId(result)
+	if uid == 0 {
+		return 0, fmt.Errorf("error authenticating %v on Odoo: %w", c.config.Login, ErrAuthentication)
+	}
+	c.auth.uid = uid
+	return uid, nil
+}
+
+// authenticateSession opens a session, whose cookie is kept by the HTTP client. It must
+// be called with the auth lock held.
+func (c *Client) authenticateSession() error {
+	result, err := c.withRetry(retryRead, func() (any, error) {
+		return c.post("/web/session/authenticate", map[string]any{
+			"db":       c.config.DB,
+			"login":    c.config.Login,
+			"password": c.config.Password,
+		})
+	})
+	if err != nil {
+		var rpcError *RPCError
+		if errors.As(err, &rpcError) && rpcError.IsException(ExceptionAccessDenied) {
+			return fmt.Errorf("error opening Odoo session for %v: %w\n>>> %w", c.config.Login, ErrAuthentication, err)
+		}
+		return fmt.Errorf("error opening Odoo session for %v:\n>>> %w", c.config.Login, err)
+	}
+	session, _ := result.(map[string]any)
+	uid := recordId(session["uid"])
+	if uid == 0 {
+		return fmt.Errorf("error opening Odoo session for %v: %w", c.config.Login, ErrAuthentication)
+	}
+	c.auth.uid = uid
+	c.auth.authenticated = true
+	return nil
+}
+
+// authenticate discovers the user ID, or opens the session, unless already done.
+func (c *Client) authenticate() error {
+	if c.config.Auth == AuthSession {
+		return c.ensureSession(false)
+	}
+	_, err := c.UserID()
+	return err
+}
+
+// callKw calls a model method as the configured user, once authenticated.
+func (c *Client) callKw(model, method string, args []any, kwargs map[string]any) (any, error) {
+	if c.config.Auth != AuthSession {
+		uid, err := c.UserID()
+		if err != nil {
+			return nil, err
+		}
+		return c.jsonRpc("object", "execute_kw", []any{c.config.DB, uid, c.config.Password, model, method, args, kwargs})
+	}
+
+	call := func() (any, error) {
+		return c.post(fmt.Sprintf("/web/dataset/call_kw/%s/%s", model, method), map[string]any{
+			"model":  model,
+			"method": method,
+			"args":   args,
+			"kwargs": kwargs,
+		})
+	}
+	result, err := call()
+	var rpcError *RPCError
+	if errors.As(err, &rpcError) && rpcError.IsException(ExceptionSessionExpired) {
+		// Sessions expire or are logged out from Odoo, authenticate again once
+		if err := c.ensureSession(true); err != nil {
+			return nil, err
+		}
+		return call()
+	}
+	return result, err
+}
+
+func (c *Client) ensureSession(renew bool) error {
+	c.auth.Lock()
+	defer c.auth.Unlock()
+	if c.auth.authenticated && !renew {
+		return nil
+	}
+	return c.authenticateSession()
+}
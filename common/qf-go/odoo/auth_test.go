@@ -0,0 +1,80 @@
+package odoo
+
+import (
+	"errors"
+	"qf/go/helpers"
+	"testing"
+)
+
+func TestClient_Authenticate(t *testing.T) {
+	requests := []rpcRequest{}
+	client, err := NewClient(Config{
+		URL:      "https://odoo.test",
+		DB:       "db",
+		Login:    "shopify@example.com",
+		Password: "api-key",
+		Transport: fakeTransport(&requests, func(request rpcRequest) (int, any) {
+			if request.Params["method"] == "authenticate" {
+				return resultResponse(9.0)
+			}
+			return resultResponse(1.0)
+		}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []*Client{client, client.WithCompany(CompanyQF), client} {
+		if _, err := c.SearchCount("res.partner", []any{}, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(requests) != 4 {
+		t.Fatalf("expected a single authentication, got %v calls", len(requests))
+	}
+	if args := requests[0].Params["args"].([]any); requests[0].Params["service"] != "common" || args[1] != "shopify@example.com" || args[2] != "api-key" {
+		t.Fatalf("unexpected authenticate call: %v", requests[0].Params)
+	}
+	if args := requests[3].Params["args"].([]any); args[1] != 9.0 || args[2] != "api-key" {
+		t.Fatalf("expected calls as the discovered user, got %v", args)
+	}
+	if uid, err := client.UserID(); err != nil || uid != 9 {
+		t.Fatalf("expected discovered user ID, got %v, %v", uid, err)
+	}
+
+	rejected, _ := NewClient(Config{
+		URL:      "https://odoo.test",
+		DB:       "db",
+		Login:    "shopify@example.com",
+		Password: "revoked",
+		Transport: fakeTransport(nil, func(rpcRequest) (int, any) {
+			return resultResponse(false)
+		}),
+	})
+	if _, err := rejected.SearchCount("res.partner", []any{}, nil); !errors.Is(err, ErrAuthentication) {
+		t.Fatalf("expected authentication error, got %v", err)
+	}
+
+	if _, err := NewClient(Config{URL: "https://odoo.test", DB: "db", UserID: 2, Password: "pwd", Auth: AuthSession}); err == nil {
+		t.Fatalf("expected error for session authentication without login")
+	}
+}
+
+func TestConfigFromEnv_Login(t *testing.T) {
+	defer helpers.TempEnvVars(map[string]string{
+		"ODOO_DB":       "db",
+		"ODOO_DOMAIN":   "odoo.test",
+		"ODOO_USER_ID":  "",
+		"ODOO_LOGIN":    "shopify@example.com",
+		"ODOO_PASSWORD": "pwd",
+		"ODOO_API_KEY":  "api-key",
+		"ODOO_AUTH":     "",
+	})()
+	config, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.UserID != 0 || config.Login != "shopify@example.com" || config.Password != "api-key" || config.Auth != AuthPassword {
+		t.Fatalf("unexpected configuration %+v", config)
+	}
+}
@@ -0,0 +1,123 @@
+package odoo
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// Cache stores the results of read calls (search_read, read, search_count...) keyed by
+// database, model, method and arguments, which include the domain, fields and context.
+type Cache struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	now     func() time.Time
+}
+
+type cacheEntry struct {
+	model   string
+	result  []byte
+	expires time.Time
+}
+
+// Entries kept before expired ones are swept.
+const cacheSweepSize = 1000
+
+func NewCache() *Cache {
+	return &Cache{entries: map[string]cacheEntry{}, now: time.Now}
+}
+
+// DefaultCache lives as long as the process, so cached lookups are reused across warm
+// invocations of the same function.
+var DefaultCache = NewCache()
+
+// Invalidate removes the cached results of the given models, or of all models when
+// none is given.
+func (c *Cache) Invalidate(models ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(models) == 0 {
+		c.entries = map[string]cacheEntry{}
+		return
+	}
+	for key, entry := range c.entries {
+		for _, model := range models {
+			if entry.model == model {
+				delete(c.entries, key)
+			}
+		}
+	}
+}
+
+func (c *Cache) get(key string) (any, bool) {
+	c.mu.Lock()
+	entry, found := c.entries[key]
+	if found && !c.now().Before(entry.expires) {
+		delete(c.entries, key)
+		found = false
+	}
+	c.mu.Unlock()
+	if !found {
+		return nil, false
+	}
+	// Decoded on every hit so callers can modify the results
+	var result any
+	if err := json.Unmarshal(entry.result, &result); err != nil {
+		return nil, false
+	}
+	return result, true
+}
+
+func (c *Cache) set(key string, model string, result any, ttl time.Duration) {
+	resultJson, err := json.Marshal(result)
+	if err != nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	if len(c.entries) >= cacheSweepSize {
+		for key, entry := range c.entries {
+			if !now.Before(entry.expires) {
+				delete(c.entries, key)
+			}
+		}
+	}
+	c.entries[key] = cacheEntry{model: model, result: resultJson, expires: now.Add(ttl)}
+}
+
+// WithCache returns a client answering read calls from cache for ttl. Any other call
+// made through the client (create, write, unlink...) invalidates the cached results of
+// its model; changes made through other clients require an explicit Invalidate.
+func (c *Client) WithCache(cache *Cache, ttl time.Duration) *Client {
+	client := *c
+	client.cache = cache
+	client.cacheTTL = ttl
+	return &client
+}
+
+// Cached is WithCache using DefaultCache.
+func (c *Client) Cached(ttl time.Duration) *Client {
+	return c.WithCache(DefaultCache, ttl)
+}
+
+func (c *Client) cachedExecuteKw(model, method string, args []any, kwargs map[string]any, call func() (any, error)) (any, error) {
+	if !isReadMethod(method) {
+		defer c.cache.Invalidate(model)
+		return call()
+	}
+	keyJson, err := json.Marshal([]any{c.config.URL, c.config.DB, c.config.UserID, c.config.Login, model, method, args, kwargs})
+	if err != nil {
+		return call()
+	}
+	key := string(keyJson)
+	if result, found := c.cache.get(key); found {
+		return result, nil
+	}
+	result, err := call()
+	if err != nil {
+		return nil, err
+	}
+	c.cache.set(key, model, result, c.cacheTTL)
+	return result, nil
+}
@@ -0,0 +1,66 @@
+package odoo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCache(t *testing.T) {
+	requests := []rpcRequest{}
+	client := newTestClient(t, &requests, func(req rpcRequest) (int, any) {
+		if req.Params["args"].([]any)[4] == "search_read" {
+			return resultResponse([]any{map[string]any{"id": 1.0, "name": "CAD"}})
+		}
+		return resultResponse(true)
+	})
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	cache := NewCache()
+	cache.now = func() time.Time { return now }
+	cached := client.WithCache(cache, time.Minute)
+
+	search := func(c *Client) {
+		t.Helper()
+		records, err := c.SearchRead("res.currency", []any{[]any{"name", "=", "CAD"}}, []string{"id"}, 0, nil)
+		if err != nil || len(records) != 1 {
+			t.Fatalf("unexpected result %v, %v", records, err)
+		}
+		// Results are copies, changing them does not affect the cache
+		records[0]["id"] = 2.0
+	}
+	expectRequests := func(expected int) {
+		t.Helper()
+		if len(requests) != expected {
+			t.Fatalf("expected %d requests, got %d", expected, len(requests))
+		}
+	}
+
+	search(cached)
+	search(cached)
+	expectRequests(1)
+	records, _ := cached.SearchRead("res.currency", []any{[]any{"name", "=", "CAD"}}, []string{"id"}, 0, nil)
+	if records[0]["id"] != 1.0 {
+		t.Fatalf("expected cached result to be unchanged, got %v", records)
+	}
+
+	// Different context, different key
+	search(cached.WithCompany(3))
+	expectRequests(2)
+
+	now = now.Add(time.Minute)
+	search(cached)
+	expectRequests(3)
+
+	if err := cached.Write("res.currency", 1, map[string]any{"active": true}, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	search(cached)
+	expectRequests(5)
+
+	cache.Invalidate("res.currency")
+	search(cached)
+	expectRequests(6)
+
+	// Clients without cache are not affected
+	search(client)
+	expectRequests(7)
+}
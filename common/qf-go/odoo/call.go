@@ -0,0 +1,119 @@
+package odoo
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Action is an action returned by a method, e.g. the window of a wizard to fill or of
+// the invoice just created.
+type Action struct {
+	Type     string // e.g. ir.actions.act_window
+	Name     string
+	ResModel string
+	ResId    int
+	Target   string // "new" for a dialog
+	Context  map[string]any
+	Raw      map[string]any
+}
+
+// IsWizard reports whether the action opens a dialog, which the method is waiting for
+// before completing, e.g. the backorder confirmation of button_validate.
+func (a Action) IsWizard() bool {
+	return a.Type == "ir.actions.act_window" && a.Target == "new"
+}
+
+// WizardError is returned by Call when the method opened a wizard instead of completing.
+type WizardError struct {
+	Model  string
+	Method string
+	Action Action
+}
+
+func (e *WizardError) Error() string {
+	return fmt.Sprintf("%v.%v opened the wizard %v (%v) instead of completing", e.Model, e.Method, e.Action.ResModel, e.Action.Name)
+}
+
+var actionType = reflect.TypeFor[Action]()
+
+// Call calls method on the records with ids (none for model methods) and decodes the
+// result into T. When T is Action, the action returned is decoded as is. Otherwise:
+//   - a wizard action returns a *WizardError
+//   - other actions, like closing the window, decode as true into a bool, and their
+//     res_id into an int, e.g. the invoice opened after _create_invoices
+//   - None decodes as true into a bool, the method having completed, and as the zero
+//     value otherwise
+//
+// A nil client uses Default.
+func Call[T any](c *Client, model string, method string, ids []int, args []any, kwargs map[string]any) (T, error) {
+	var decoded T
+	c, err := orDefault(c)
+	if err != nil {
+		return decoded, err
+	}
+	callArgs := args
+	if ids != nil {
+		callArgs = append([]any{ids}, args...)
+	}
+	if callArgs == nil {
+		callArgs = []any{}
+	}
+	result, err := c.JsonRpcExecuteKw(model, method, callArgs, kwargs)
+	if err != nil {
+		return decoded, err
+	}
+
+	target := reflect.ValueOf(&decoded).Elem()
+	if action, isAction := parseAction(result); isAction {
+		switch {
+		case target.Type() == actionType:
+			target.Set(reflect.ValueOf(action))
+			return decoded, nil
+		case action.IsWizard():
+			return decoded, &WizardError{Model: model, Method: method, Action: action}
+		case target.Kind() == reflect.Bool:
+			target.SetBool(true)
+			return decoded, nil
+		case target.Kind() == reflect.Int && action.ResId != 0:
+			target.SetInt(int64(action.ResId))
+			return decoded, nil
+		}
+		return decoded, fmt.Errorf("cannot decode action %v returned by %v.%v into %v", action.Type, model, method, target.Type())
+	}
+	if result == nil {
+		if target.Kind() == reflect.Bool {
+			target.SetBool(true)
+		}
+		return decoded, nil
+	}
+
+	resultJson, err := json.Marshal(result)
+	if err != nil {
+		return decoded, fmt.Errorf("invalid result from %v.%v:\n>>> %w", model, method, err)
+	}
+	if err := json.Unmarshal(resultJson, &decoded); err != nil {
+		return decoded, fmt.Errorf("cannot decode result of %v.%v into %v: %s\n>>> %w", model, method, target.Type(), resultJson, err)
+	}
+	return decoded, nil
+}
+
+// parseAction returns the action of a result that is an ir.actions.* dictionary.
+func parseAction(result any) (Action, bool) {
+	raw, isMap := result.(map[string]any)
+	if !isMap {
+		return Action{}, false
+	}
+	actionType, _ := raw["type"].(string)
+	if !strings.HasPrefix(actionType, "ir.actions.") {
+		return Action{}, false
+	}
+	action := Action{Type: actionType, Raw: raw}
+	action.Name, _ = raw["name"].(string)
+	action.ResModel, _ = raw["res_model"].(string)
+	action.ResId = recordId(raw["res_id"])
+	action.Target, _ = raw["target"].(string)
+	action.Context, _ = raw["context"].(map[string]any)
+	return action, true
+}
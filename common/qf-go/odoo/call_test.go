@@ -0,0 +1,60 @@
+package odoo
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCall(t *testing.T) {
+	requests := []rpcRequest{}
+	client := newTestClient(t, &requests, func(request rpcRequest) (int, any) {
+		switch request.Params["args"].([]any)[4] {
+		case "action_confirm":
+			return resultResponse(true)
+		case "action_cancel":
+			return resultResponse(nil)
+		case "_create_invoices":
+			return resultResponse(map[string]any{"type": "ir.actions.act_window", "res_model": "account.move", "res_id": 42.0, "target": "current"})
+		case "button_validate":
+			return resultResponse(map[string]any{"type": "ir.actions.act_window", "name": "Create Backorder?", "res_model": "stock.backorder.confirmation", "res_id": false, "target": "new"})
+		case "action_post":
+			return resultResponse(map[string]any{"type": "ir.actions.act_window_close"})
+		case "search":
+			return resultResponse([]any{1.0, 2.0})
+		}
+		return resultResponse(false)
+	})
+
+	if confirmed, err := Call[bool](client, "sale.order", "action_confirm", []int{1}, nil, nil); err != nil || !confirmed {
+		t.Fatalf("expected true, got %v, %v", confirmed, err)
+	}
+	if args := requests[0].Params["args"].([]any)[5]; !reflect.DeepEqual(args, []any{[]any{1.0}}) {
+		t.Fatalf("expected ids as first argument, got %v", args)
+	}
+	if cancelled, err := Call[bool](client, "sale.order", "action_cancel", []int{1}, nil, nil); err != nil || !cancelled {
+		t.Fatalf("expected None to decode as true, got %v, %v", cancelled, err)
+	}
+	if invoiceId, err := Call[int](client, "sale.order", "_create_invoices", []int{1}, nil, nil); err != nil || invoiceId != 42 {
+		t.Fatalf("expected the invoice ID of the action, got %v, %v", invoiceId, err)
+	}
+	if posted, err := Call[bool](client, "account.move", "action_post", []int{42}, nil, nil); err != nil || !posted {
+		t.Fatalf("expected closing action to decode as true, got %v, %v", posted, err)
+	}
+	if ids, err := Call[[]int](client, "res.partner", "search", nil, []any{[]any{}}, nil); err != nil || !reflect.DeepEqual(ids, []int{1, 2}) {
+		t.Fatalf("expected decoded IDs, got %v, %v", ids, err)
+	}
+	if args := requests[4].Params["args"].([]any)[5]; !reflect.DeepEqual(args, []any{[]any{}}) {
+		t.Fatalf("expected no ids for model methods, got %v", args)
+	}
+
+	_, err := Call[bool](client, "stock.picking", "button_validate", []int{7}, nil, nil)
+	var wizardError *WizardError
+	if !errors.As(err, &wizardError) || wizardError.Action.ResModel != "stock.backorder.confirmation" {
+		t.Fatalf("expected WizardError, got %v", err)
+	}
+	action, err := Call[Action](client, "stock.picking", "button_validate", []int{7}, nil, nil)
+	if err != nil || !action.IsWizard() || action.Name != "Create Backorder?" {
+		t.Fatalf("expected the wizard action, got %v, %v", action, err)
+	}
+}
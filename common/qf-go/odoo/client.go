@@ -0,0 +1,256 @@
+package odoo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var DefaultTimeout = 30 * time.Second
+
+// Config holds everything needed to talk to one Odoo database.
+type Config struct {
+	URL       string // Base URL of the Odoo server, e.g. https://example.odoo.com
+	DB        string
+	UserID    int    // Discovered from Login when 0
+	Login     string // Required when UserID is 0 or for AuthSession
+	Password  string // Password or API key of the user
+	Auth      AuthMethod
+	Timeout   time.Duration     // Defaults to DefaultTimeout
+	Transport http.RoundTripper // Defaults to http.DefaultTransport
+	Retry     RetryPolicy       // Defaults to DefaultRetryPolicy
+
+	// Check values against the model fields before every create and write
+	ValidateFields bool
+	// Server version such as "16.0", detected through common.version when empty
+	Version string
+}
+
+// Client talks to a single Odoo database through JSON-RPC. A Client is safe for
+// concurrent use and keeps its HTTP connections alive between calls.
+type Client struct {
+	config     Config
+	httpClient *http.Client
+	auth       *authState
+	sleep      func(time.Duration)
+	context    Context
+	plan       *Plan // Dry-run plan capturing writes, see WithDryRun
+	cache      *Cache
+	cacheTTL   time.Duration
+
+	interceptors   []Interceptor
+	unlinkPolicies map[string]UnlinkPolicy
+}
+
+func NewClient(config Config) (*Client, error) {
+	if !(config.URL != "" && config.DB != "" && (config.UserID != 0 || config.Login != "") && config.Password != "") {
+		return nil, fmt.Errorf("invalid or incomplete Odoo client configuration")
+	}
+	if config.Auth != AuthPassword && config.Auth != AuthSession {
+		return nil, fmt.Errorf("invalid Odoo client configuration: unknown authentication %q", config.Auth)
+	}
+	if config.Auth == AuthSession && config.Login == "" {
+		return nil, fmt.Errorf("invalid Odoo client configuration: a login is required for session authentication")
+	}
+	config.URL = strings.TrimRight(config.URL, "/")
+	if config.Timeout == 0 {
+		config.Timeout = DefaultTimeout
+	}
+	if config.Retry == (RetryPolicy{}) {
+		config.Retry = DefaultRetryPolicy
+	}
+	httpClient := &http.Client{
+		Timeout:   config.Timeout,
+		Transport: config.Transport,
+	}
+	if config.Auth == AuthSession {
+		httpClient.Jar, _ = cookiejar.New(nil)
+	}
+	return &Client{
+		config:     config,
+		httpClient: httpClient,
+		auth:       &authState{},
+		sleep:      time.Sleep,
+	}, nil
+}
+
+// ConfigFromEnv reads the Odoo configuration from ODOO_DOMAIN, ODOO_DB, ODOO_PASSWORD
+// or ODOO_API_KEY, and ODOO_USER_ID or ODOO_LOGIN, from which the user ID is discovered.
+// Optionally ODOO_AUTH=session, ODOO_VALIDATE_FIELDS=true and ODOO_VERSION.
+func ConfigFromEnv() (Config, error) {
+	db := os.Getenv("ODOO_DB")
+	uid := os.Getenv("ODOO_USER_ID")
+	login := os.Getenv("ODOO_LOGIN")
+	pwd := os.Getenv("ODOO_PASSWORD")
+	if apiKey := os.Getenv("ODOO_API_KEY"); apiKey != "" {
+		pwd = apiKey
+	}
+	domain := os.Getenv("ODOO_DOMAIN")
+	if !(db != "" && domain != "" && (uid != "" || login != "") && pwd != "") {
+		return Config{}, fmt.Errorf("invalid or incomplete Odoo environment variables")
+	}
+	uidInt := 0
+	if uid != "" {
+		var err error
+		uidInt, err = strconv.Atoi(uid)
+		if err != nil {
+			return Config{}, fmt.Errorf("invalid Odoo user ID %v in environment variables:\n>>> %w", uid, err)
+		}
+	}
+	return Config{
+		URL:            fmt.Sprintf("https://%s", domain),
+		DB:             db,
+		UserID:         uidInt,
+		Login:          login,
+		Password:       pwd,
+		Auth:           AuthMethod(os.Getenv("ODOO_AUTH")),
+		ValidateFields: os.Getenv("ODOO_VALIDATE_FIELDS") == "true",
+		Version:        os.Getenv("ODOO_VERSION"),
+	}, nil
+}
+
+func NewClientFromEnv() (*Client, error) {
+	config, err := ConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(config)
+}
+
+var defaultClient struct {
+	sync.Mutex
+	client   *Client
+	config   Config
+	override *Client
+}
+
+// Default returns the client used by the package-level functions. Unless one was
+// set with SetDefault, it is built from the environment and reused for as long as
+// the environment does not change, so warm invocations share connections.
+func Default() (*Client, error) {
+	defaultClient.Lock()
+	defer defaultClient.Unlock()
+	if defaultClient.override != nil {
+		return defaultClient.override, nil
+	}
+	config, err := ConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	if defaultClient.client != nil && defaultClient.config == config {
+		return defaultClient.client, nil
+	}
+	client, err := NewClient(config)
+	if err != nil {
+		return nil, err
+	}
+	defaultClient.client = client
+	defaultClient.config = config
+	return client, nil
+}
+
+func SetDefault(client *Client) (reset func()) {
+	defaultClient.Lock()
+	defer defaultClient.Unlock()
+	current := defaultClient.override
+	defaultClient.override = client
+	return func() {
+		defaultClient.Lock()
+		defer defaultClient.Unlock()
+		defaultClient.override = current
+	}
+}
+
+func (c *Client) Config() Config {
+	return c.config
+}
+
+func (c *Client) jsonRpc(service string, method string, args []any) (any, error) {
+	return c.post("/jsonrpc", map[string]any{
+		"service": service,
+		"method":  method,
+		"args":    args,
+	})
+}
+
+// post sends a JSON-RPC request to path, e.g. /jsonrpc or a route of the web client.
+func (c *Client) post(path string, params map[string]any) (any, error) {
+	url := c.config.URL + path
+	body := map[string]any{
+		"jsonrpc": "2.0",
+		"method":  "call",
+		"id":      c.config.UserID,
+		"params":  params,
+	}
+
+	bodyJson, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("could not serialize json for Odoo JSON-RPC call:\n>>> %w", err)
+	}
+
+	response, err := c.httpClient.Post(url, "application/json", bytes.NewBuffer(bodyJson))
+	if err != nil {
+		return nil, fmt.Errorf("request error during Odoo JSON-RPC call:\n>>> %w", err)
+	}
+	defer response.Body.Close()
+
+	rBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response from Odoo JSON-RPC call:\n>>> %w", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return nil, &HTTPError{StatusCode: response.StatusCode, Status: response.Status, Body: string(rBody)}
+	}
+
+	rJson := map[string]any{}
+	err = json.Unmarshal(rBody, &rJson)
+	if err != nil {
+		return nil, fmt.Errorf("invalid json response from Odoo JSON-RPC call: %s\n>>> %w", string(rBody), err)
+	}
+
+	rError, rErrorExist := rJson["error"]
+	if rErrorExist {
+		return nil, parseRPCError(rError)
+	}
+
+	result, resultOk := rJson["result"]
+	if !resultOk {
+		return nil, fmt.Errorf("result not found in response from Odoo JSON-RPC call: %s", string(rBody))
+	}
+
+	return result, nil
+}
+
+func (c *Client) executeKw(model, method string, args []any, kwargs map[string]any) (any, error) {
+	if c.plan != nil && !isReadMethod(method) {
+		return c.plan.record(model, method, args, kwargs)
+	}
+	call := func() (any, error) {
+		// Authenticated first, as its own calls are retried already
+		if err := c.authenticate(); err != nil {
+			return nil, err
+		}
+		return c.withRetry(retryKindOf(method), func() (any, error) {
+			return c.callKw(model, method, args, kwargs)
+		})
+	}
+	if len(c.interceptors) != 0 {
+		send := call
+		call = func() (any, error) {
+			return c.intercept(model, method, args, kwargs, send)
+		}
+	}
+	if c.cache != nil {
+		return c.cachedExecuteKw(model, method, args, kwargs, call)
+	}
+	return call()
+}
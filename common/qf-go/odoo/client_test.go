@@ -0,0 +1,173 @@
+package odoo
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"qf/go/helpers"
+	"testing"
+	"time"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+type rpcRequest struct {
+	URL    string
+	Params map[string]any
+}
+
+// fakeTransport answers every JSON-RPC call with respond(request) and records the requests received.
+func fakeTransport(requests *[]rpcRequest, respond func(rpcRequest) (int, any)) http.RoundTripper {
+	return roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		body, _ := io.ReadAll(r.Body)
+		payload := map[string]any{}
+		json.Unmarshal(body, &payload)
+		req := rpcRequest{URL: r.URL.String()}
+		req.Params, _ = payload["params"].(map[string]any)
+		if requests != nil {
+			*requests = append(*requests, req)
+		}
+		status, response := respond(req)
+		responseJson, _ := json.Marshal(response)
+		return &http.Response{
+			StatusCode: status,
+			Status:     http.StatusText(status),
+			Body:       io.NopCloser(bytes.NewReader(responseJson)),
+			Header:     http.Header{},
+		}, nil
+	})
+}
+
+func resultResponse(result any) (int, any) {
+	return 200, map[string]any{"jsonrpc": "2.0", "id": 1, "result": result}
+}
+
+func newTestClient(t *testing.T, requests *[]rpcRequest, respond func(rpcRequest) (int, any)) *Client {
+	t.Helper()
+	client, err := NewClient(Config{
+		URL:       "https://odoo.test/",
+		DB:        "db",
+		UserID:    2,
+		Password:  "pwd",
+		Transport: fakeTransport(requests, respond),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error creating client: %v", err)
+	}
+	client.sleep = func(time.Duration) {}
+	return client
+}
+
+func TestNewClient_InvalidConfig(t *testing.T) {
+	_, err := NewClient(Config{URL: "https://odoo.test", DB: "db"})
+	if err == nil {
+		t.Fatalf("expected error for incomplete configuration")
+	}
+}
+
+func TestClient_ExecuteKw(t *testing.T) {
+	requests := []rpcRequest{}
+	client := newTestClient(t, &requests, func(rpcRequest) (int, any) {
+		return resultResponse(3.0)
+	})
+	count, err := client.SearchCount("res.partner", []any{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count != 3 {
+		t.Fatalf("expected count 3, got %v", count)
+	}
+	if len(requests) != 1 || requests[0].URL != "https://odoo.test/jsonrpc" {
+		t.Fatalf("unexpected requests: %v", requests)
+	}
+	args := requests[0].Params["args"].([]any)
+	if args[0] != "db" || args[1] != 2.0 || args[2] != "pwd" || args[3] != "res.partner" || args[4] != "search_count" {
+		t.Fatalf("unexpected execute_kw args: %v", args)
+	}
+}
+
+func TestDefault(t *testing.T) {
+	defer helpers.TempEnvVars(map[string]string{"ODOO_DB": "", "ODOO_USER_ID": "", "ODOO_LOGIN": "", "ODOO_PASSWORD": "", "ODOO_API_KEY": "", "ODOO_DOMAIN": ""})()
+	if _, err := Default(); err == nil {
+		t.Fatalf("expected error without environment variables")
+	}
+	if _, err := SearchCount("res.partner", []any{}, nil); err == nil {
+		t.Fatalf("expected error from package-level function without environment variables")
+	}
+
+	defer helpers.TempEnvVars(map[string]string{"ODOO_DB": "db", "ODOO_USER_ID": "2", "ODOO_PASSWORD": "pwd", "ODOO_DOMAIN": "odoo.test"})()
+	first, err := Default()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, _ := Default()
+	if first != second {
+		t.Fatalf("expected default client to be reused while the environment does not change")
+	}
+
+	client := newTestClient(t, nil, func(rpcRequest) (int, any) {
+		return resultResponse(7.0)
+	})
+	reset := SetDefault(client)
+	count, err := SearchCount("res.partner", []any{}, nil)
+	reset()
+	if err != nil || count != 7 {
+		t.Fatalf("expected package-level function to use the overridden client, got %v, %v", count, err)
+	}
+	if current, _ := Default(); current != first {
+		t.Fatalf("expected default client to be restored after reset")
+	}
+}
+
+func TestClient_RPCError(t *testing.T) {
+	client := newTestClient(t, nil, func(rpcRequest) (int, any) {
+		return 200, map[string]any{"jsonrpc": "2.0", "id": 1, "error": map[string]any{
+			"code":    200.0,
+			"message": "Odoo Server Error",
+			"data": map[string]any{
+				"name":    ExceptionValidation,
+				"message": "Invalid field",
+				"debug":   "Traceback...",
+			},
+		}}
+	})
+	_, err := client.SearchCount("res.partner", []any{}, nil)
+	var rpcError *RPCError
+	if !errors.As(err, &rpcError) {
+		t.Fatalf("expected RPCError, got %T: %v", err, err)
+	}
+	if rpcError.Code != 200 || rpcError.Detail != "Invalid field" || rpcError.Debug != "Traceback..." {
+		t.Fatalf("unexpected error values: %#v", rpcError)
+	}
+	if !IsRPCException(err, ExceptionValidation) || !IsRPCException(err, "ValidationError") || IsRPCException(err, ExceptionAccess) {
+		t.Fatalf("unexpected exception matching for %v", rpcError.Name)
+	}
+}
+
+func TestClient_SearchReadOneErrors(t *testing.T) {
+	tests := []struct {
+		Title    string
+		Count    float64
+		Expected error
+	}{
+		{Title: "Not found", Count: 0, Expected: ErrNotFound},
+		{Title: "Multiple results", Count: 2, Expected: ErrMultipleResults},
+	}
+	for _, tc := range tests {
+		t.Run(tc.Title, func(t *testing.T) {
+			client := newTestClient(t, nil, func(rpcRequest) (int, any) {
+				return resultResponse(tc.Count)
+			})
+			_, err := client.SearchReadOne("res.partner", []any{}, []string{"id"}, nil)
+			if !errors.Is(err, tc.Expected) {
+				t.Fatalf("expected %v, got %v", tc.Expected, err)
+			}
+		})
+	}
+}
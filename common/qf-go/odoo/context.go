@@ -0,0 +1,63 @@
+package odoo
+
+import (
+	"maps"
+)
+
+// Context is an Odoo context dictionary. It is never modified in place: Merge and the
+// Client.With* methods always return new values, so clients derived from the same
+// parent can be used concurrently without leaking keys into each other.
+type Context map[string]any
+
+// Merge returns a new context with values added on top of c.
+func (c Context) Merge(values map[string]any) Context {
+	merged := make(Context, len(c)+len(values))
+	maps.Copy(merged, c)
+	maps.Copy(merged, values)
+	return merged
+}
+
+// Context returns a copy of the context sent with every call made by the client.
+func (c *Client) Context() Context {
+	return c.context.Merge(nil)
+}
+
+// WithContext returns a client sharing the same connection and configuration whose
+// calls carry values on top of the current context. Values passed in a call's own
+// context still take precedence.
+func (c *Client) WithContext(values map[string]any) *Client {
+	client := *c
+	client.context = c.context.Merge(values)
+	return &client
+}
+
+func (c *Client) WithCompany(companyIds ...int) *Client {
+	return c.WithContext(map[string]any{"allowed_company_ids": companyIds})
+}
+
+func (c *Client) WithLang(lang string) *Client {
+	return c.WithContext(map[string]any{"lang": lang})
+}
+
+func (c *Client) WithTimezone(tz string) *Client {
+	return c.WithContext(map[string]any{"tz": tz})
+}
+
+func (c *Client) WithActiveTest(activeTest bool) *Client {
+	return c.WithContext(map[string]any{"active_test": activeTest})
+}
+
+func (c *Client) WithTrackingDisabled() *Client {
+	return c.WithContext(map[string]any{"tracking_disable": true})
+}
+
+// callContext merges the context given to a single call on top of the client context.
+func (c *Client) callContext(callContext any) Context {
+	switch callContext := callContext.(type) {
+	case Context:
+		return c.context.Merge(callContext)
+	case map[string]any:
+		return c.context.Merge(callContext)
+	}
+	return c.context.Merge(nil)
+}
@@ -0,0 +1,45 @@
+package odoo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClient_WithContext(t *testing.T) {
+	requests := []rpcRequest{}
+	base := newTestClient(t, &requests, func(rpcRequest) (int, any) {
+		return resultResponse(1.0)
+	})
+	qf := base.WithCompany(CompanyQF).WithLang("en_CA")
+	fm := qf.WithCompany(CompanyFM)
+
+	callContext := map[string]any{"lang": "fr_CA", "active_test": false}
+	kwargs := map[string]any{"context": callContext}
+	if _, err := fm.JsonRpcExecuteKw("res.partner", "search_count", []any{[]any{}}, kwargs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := qf.SearchCount("res.partner", []any{}, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := base.SearchCount("res.partner", []any{}, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []map[string]any{
+		{"allowed_company_ids": []any{float64(CompanyFM)}, "lang": "fr_CA", "active_test": false},
+		{"allowed_company_ids": []any{float64(CompanyQF)}, "lang": "en_CA"},
+		{},
+	}
+	for i, request := range requests {
+		sent := request.Params["args"].([]any)[6].(map[string]any)["context"]
+		if !reflect.DeepEqual(sent, expected[i]) {
+			t.Fatalf("request %d: expected context %v, got %v", i, expected[i], sent)
+		}
+	}
+	if len(callContext) != 2 || len(kwargs) != 1 {
+		t.Fatalf("call arguments were modified: %v, %v", callContext, kwargs)
+	}
+	if !reflect.DeepEqual(base.Context(), Context{}) {
+		t.Fatalf("base client context was modified: %v", base.Context())
+	}
+}
@@ -0,0 +1,210 @@
+package odoo
+
+import (
+	"fmt"
+	"qf/go/helpers"
+	"strings"
+	"time"
+)
+
+// CountriesCacheTTL is how long the country and state tables are cached when resolving
+// addresses through a client without cache.
+var CountriesCacheTTL = time.Hour
+
+// CountryAliases maps country codes and names in use that are neither ISO codes nor Odoo
+// names to their ISO2 code.
+var CountryAliases = map[string]string{
+	"UK":                       "GB",
+	"United States of America": "US",
+}
+
+// StateAliases maps, by country ISO2 code, the legacy or alternative codes and names of
+// states to their Odoo code.
+var StateAliases = map[string]map[string]string{
+	"CA": {
+		"PQ":           "QC",
+		"NF":           "NL",
+		"LB":           "NL",
+		"YK":           "YT",
+		"NWT":          "NT",
+		"Newfoundland": "NL",
+		"PEI":          "PE",
+	},
+}
+
+type State struct {
+	ID   int    `odoo:"id"`
+	Code string `odoo:"code"`
+	Name string `odoo:"name"`
+}
+
+type Country struct {
+	ID     int    `odoo:"id"`
+	Code   string `odoo:"code"`
+	Name   string `odoo:"name"`
+	States []State
+}
+
+type countryState struct {
+	ID      int      `odoo:"id"`
+	Code    string   `odoo:"code"`
+	Name    string   `odoo:"name"`
+	Country Many2One `odoo:"country_id"`
+}
+
+// Countries returns all the countries with their states, read in two calls answered from
+// cache afterwards.
+func (c *Client) Countries() ([]Country, error) {
+	if c.cache == nil {
+		c = c.Cached(CountriesCacheTTL)
+	}
+	countries, err := SearchReadInto[Country](c, "res.country", []any{}, 0, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error reading countries:\n>>> %w", err)
+	}
+	states, err := SearchReadInto[countryState](c, "res.country.state", []any{}, 0, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error reading states:\n>>> %w", err)
+	}
+	indexes := map[int]int{}
+	for i, country := range countries {
+		indexes[country.ID] = i
+	}
+	for _, state := range states {
+		if i, found := indexes[state.Country.ID]; found {
+			countries[i].States = append(countries[i].States, State{ID: state.ID, Code: state.Code, Name: state.Name})
+		}
+	}
+	return countries, nil
+}
+
+// ResolveCountryState returns the IDs of a country, given by ISO2 or ISO3 code or by
+// name, and of one of its states, given by code or name. Names are compared ignoring
+// case and accents, and CountryAliases and StateAliases are accepted too. An empty
+// country or state, or a state of a country without states in Odoo, resolves to 0. An
+// unknown one returns an error wrapping ErrNotFound.
+func (c *Client) ResolveCountryState(country string, state string) (countryId int, stateId int, err error) {
+	country = strings.TrimSpace(country)
+	state = strings.TrimSpace(state)
+	if country == "" {
+		if state != "" {
+			return 0, 0, fmt.Errorf("cannot resolve state %q without country:\n>>> %w", state, ErrNotFound)
+		}
+		return 0, 0, nil
+	}
+	countries, err := c.Countries()
+	if err != nil {
+		return 0, 0, err
+	}
+	found, err := findCountry(countries, country)
+	if err != nil {
+		return 0, 0, err
+	}
+	if state == "" {
+		return found.ID, 0, nil
+	}
+	foundState, err := findState(found, state)
+	if err != nil {
+		return found.ID, 0, err
+	}
+	return found.ID, foundState.ID, nil
+}
+
+// GetCountryAndStateIds returns 0 for the country or state that could not be resolved.
+//
+// Deprecated: use ResolveCountryState, which returns why.
+func (c *Client) GetCountryAndStateIds(countryCode string, stateCode string) (int, int) {
+	countryId, stateId, _ := c.ResolveCountryState(countryCode, stateCode)
+	return countryId, stateId
+}
+
+func findCountry(countries []Country, country string) (Country, error) {
+	code := strings.ToUpper(country)
+	for alias, aliasCode := range CountryAliases {
+		if equal, _ := helpers.CompareStrings(alias, country); equal {
+			code = aliasCode
+		}
+	}
+	if iso2, found := iso3Codes[code]; found {
+		code = iso2
+	}
+	for _, found := range countries {
+		if found.Code == code {
+			return found, nil
+		}
+	}
+	for _, found := range countries {
+		equal, err := helpers.CompareStrings(found.Name, country)
+		if err != nil {
+			return Country{}, fmt.Errorf("error comparing country %q with %q:\n>>> %w", country, found.Name, err)
+		}
+		if equal {
+			return found, nil
+		}
+	}
+	return Country{}, fmt.Errorf("unknown country %q:\n>>> %w", country, ErrNotFound)
+}
+
+func findState(country Country, state string) (State, error) {
+	if len(country.States) == 0 {
+		// States are not tracked in Odoo for this country
+		return State{}, nil
+	}
+	code := strings.ToUpper(state)
+	for alias, aliasCode := range StateAliases[country.Code] {
+		if equal, _ := helpers.CompareStrings(alias, state); equal {
+			code = aliasCode
+		}
+	}
+	for _, found := range country.States {
+		if strings.EqualFold(found.Code, code) {
+			return found, nil
+		}
+	}
+	for _, found := range country.States {
+		equal, err := helpers.CompareStrings(found.Name, state)
+		if err != nil {
+			return State{}, fmt.Errorf("error comparing state %q with %q:\n>>> %w", state, found.Name, err)
+		}
+		if equal {
+			return found, nil
+		}
+	}
+	return State{}, fmt.Errorf("unknown state %q of %v:\n>>> %w", state, country.Name, ErrNotFound)
+}
+
+// ISO 3166-1 alpha-3 codes, Odoo only knows the alpha-2 ones.
+var iso3Codes = map[string]string{
+	"ABW": "AW", "AFG": "AF", "AGO": "AO", "AIA": "AI", "ALA": "AX", "ALB": "AL", "AND": "AD", "ARE": "AE",
+	"ARG": "AR", "ARM": "AM", "ASM": "AS", "ATA": "AQ", "ATF": "TF", "ATG": "AG", "AUS": "AU", "AUT": "AT",
+	"AZE": "AZ", "BDI": "BI", "BEL": "BE", "BEN": "BJ", "BES": "BQ", "BFA": "BF", "BGD": "BD", "BGR": "BG",
+	"BHR": "BH", "BHS": "BS", "BIH": "BA", "BLM": "BL", "BLR": "BY", "BLZ": "BZ", "BMU": "BM", "BOL": "BO",
+	"BRA": "BR", "BRB": "BB", "BRN": "BN", "BTN": "BT", "BVT": "BV", "BWA": "BW", "CAF": "CF", "CAN": "CA",
+	"CCK": "CC", "CHE": "CH", "CHL": "CL", "CHN": "CN", "CIV": "CI", "CMR": "CM", "COD": "CD", "COG": "CG",
+	"COK": "CK", "COL": "CO", "COM": "KM", "CPV": "CV", "CRI": "CR", "CUB": "CU", "CUW": "CW", "CXR": "CX",
+	"CYM": "KY", "CYP": "CY", "CZE": "CZ", "DEU": "DE", "DJI": "DJ", "DMA": "DM", "DNK": "DK", "DOM": "DO",
+	"DZA": "DZ", "ECU": "EC", "EGY": "EG", "ERI": "ER", "ESH": "EH", "ESP": "ES", "EST": "EE", "ETH": "ET",
+	"FIN": "FI", "FJI": "FJ", "FLK": "FK", "FRA": "FR", "FRO": "FO", "FSM": "FM", "GAB": "GA", "GBR": "GB",
+	"GEO": "GE", "GGY": "GG", "GHA": "GH", "GIB": "GI", "GIN": "GN", "GLP": "GP", "GMB": "GM", "GNB": "GW",
+	"GNQ": "GQ", "GRC": "GR", "GRD": "GD", "GRL": "GL", "GTM": "GT", "GUF": "GF", "GUM": "GU", "GUY": "GY",
+	"HKG": "HK", "HMD": "HM", "HND": "HN", "HRV": "HR", "HTI": "HT", "HUN": "HU", "IDN": "ID", "IMN": "IM",
+	"IND": "IN", "IOT": "IO", "IRL": "IE", "IRN": "IR", "IRQ": "IQ", "ISL": "IS", "ISR": "IL", "ITA": "IT",
+	"JAM": "JM", "JEY": "JE", "JOR": "JO", "JPN": "JP", "KAZ": "KZ", "KEN": "KE", "KGZ": "KG", "KHM": "KH",
+	"KIR": "KI", "KNA": "KN", "KOR": "KR", "KWT": "KW", "LAO": "LA", "LBN": "LB", "LBR": "LR", "LBY": "LY",
+	"LCA": "LC", "LIE": "LI", "LKA": "LK", "LSO": "LS", "LTU": "LT", "LUX": "LU", "LVA": "LV", "MAC": "MO",
+	"MAF": "MF", "MAR": "MA", "MCO": "MC", "MDA": "MD", "MDG": "MG", "MDV": "MV", "MEX": "MX", "MHL": "MH",
+	"MKD": "MK", "MLI": "ML", "MLT": "MT", "MMR": "MM", "MNE": "ME", "MNG": "MN", "MNP": "MP", "MOZ": "MZ",
+	"MRT": "MR", "MSR": "MS", "MTQ": "MQ", "MUS": "MU", "MWI": "MW", "MYS": "MY", "MYT": "YT", "NAM": "NA",
+	"NCL": "NC", "NER": "NE", "NFK": "NF", "NGA": "NG", "NIC": "NI", "NIU": "NU", "NLD": "NL", "NOR": "NO",
+	"NPL": "NP", "NRU": "NR", "NZL": "NZ", "OMN": "OM", "PAK": "PK", "PAN": "PA", "PCN": "PN", "PER": "PE",
+	"PHL": "PH", "PLW": "PW", "PNG": "PG", "POL": "PL", "PRI": "PR", "PRK": "KP", "PRT": "PT", "PRY": "PY",
+	"PSE": "PS", "PYF": "PF", "QAT": "QA", "REU": "RE", "ROU": "RO", "RUS": "RU", "RWA": "RW", "SAU": "SA",
+	"SDN": "SD", "SEN": "SN", "SGP": "SG", "SGS": "GS", "SHN": "SH", "SJM": "SJ", "SLB": "SB", "SLE": "SL",
+	"SLV": "SV", "SMR": "SM", "SOM": "SO", "SPM": "PM", "SRB": "RS", "SSD": "SS", "STP": "ST", "SUR": "SR",
+	"SVK": "SK", "SVN": "SI", "SWE": "SE", "SWZ": "SZ", "SXM": "SX", "SYC": "SC", "SYR": "SY", "TCA": "TC",
+	"TCD": "TD", "TGO": "TG", "THA": "TH", "TJK": "TJ", "TKL": "TK", "TKM": "TM", "TLS": "TL", "TON": "TO",
+	"TTO": "TT", "TUN": "TN", "TUR": "TR", "TUV": "TV", "TWN": "TW", "TZA": "TZ", "UGA": "UG", "UKR": "UA",
+	"UMI": "UM", "URY": "UY", "USA": "US", "UZB": "UZ", "VAT": "VA", "VCT": "VC", "VEN": "VE", "VGB": "VG",
+	"VIR": "VI", "VNM": "VN", "VUT": "VU", "WLF": "WF", "WSM": "WS", "XKX": "XK", "YEM": "YE", "ZAF": "ZA",
+	"ZMB": "ZM", "ZWE": "ZW",
+}
@@ -0,0 +1,54 @@
+package odoo
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestResolveCountryState(t *testing.T) {
+	requests := []rpcRequest{}
+	client := newTestClient(t, &requests, func(request rpcRequest) (int, any) {
+		if request.Params["args"].([]any)[3] == "res.country" {
+			return resultResponse([]any{
+				map[string]any{"id": 1.0, "code": "CA", "name": "Canada"},
+				map[string]any{"id": 2.0, "code": "US", "name": "United States"},
+				map[string]any{"id": 3.0, "code": "FR", "name": "France"},
+			})
+		}
+		return resultResponse([]any{
+			map[string]any{"id": 10.0, "code": "QC", "name": "Quebec", "country_id": []any{1.0, "Canada"}},
+			map[string]any{"id": 11.0, "code": "NL", "name": "Newfoundland and Labrador", "country_id": []any{1.0, "Canada"}},
+			map[string]any{"id": 20.0, "code": "CA", "name": "California", "country_id": []any{2.0, "United States"}},
+		})
+	}).WithCache(NewCache(), CountriesCacheTTL)
+
+	for _, test := range []struct {
+		country, state     string
+		countryId, stateId int
+	}{
+		{"CA", "QC", 1, 10},
+		{"ca", "qc", 1, 10},
+		{"CAN", "Québec", 1, 10},
+		{"Canada", "PQ", 1, 10},
+		{" canadá ", "NF", 1, 11},
+		{"USA", "California", 2, 20},
+		{"United States of America", "CA", 2, 20},
+		{"FRA", "Île-de-France", 3, 0},
+		{"US", "", 2, 0},
+		{"", "", 0, 0},
+	} {
+		countryId, stateId, err := client.ResolveCountryState(test.country, test.state)
+		if err != nil || countryId != test.countryId || stateId != test.stateId {
+			t.Errorf("expected %q, %q to resolve to %v, %v, got %v, %v, %v", test.country, test.state, test.countryId, test.stateId, countryId, stateId, err)
+		}
+	}
+	if len(requests) != 2 {
+		t.Fatalf("expected the tables to be read once, got %v calls", len(requests))
+	}
+
+	for _, test := range [][2]string{{"Narnia", ""}, {"CA", "Ontario"}, {"", "QC"}} {
+		if _, _, err := client.ResolveCountryState(test[0], test[1]); !errors.Is(err, ErrNotFound) {
+			t.Errorf("expected ErrNotFound for %q, %q, got %v", test[0], test[1], err)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package odoo
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Datetime is the value of a datetime field, which Odoo stores in UTC without timezone
+// and converts to the timezone of the user for display. It is always written in UTC
+// whatever the location of the time, and the zero value is written as false.
+type Datetime struct {
+	time.Time
+}
+
+// NewDatetime returns the datetime of t, converted to UTC.
+func NewDatetime(t time.Time) Datetime {
+	return Datetime{t.UTC()}
+}
+
+func Now() Datetime {
+	return NewDatetime(time.Now())
+}
+
+// ParseDatetime parses a datetime formatted with DateFormat, as returned by Odoo.
+func ParseDatetime(value string) (Datetime, error) {
+	parsed, err := time.ParseInLocation(DateFormat, value, time.UTC)
+	if err != nil {
+		return Datetime{}, fmt.Errorf("invalid datetime %q: %w", value, err)
+	}
+	return Datetime{parsed}, nil
+}
+
+// InTimezone returns the datetime in timezone tz, e.g. the tz of a res.users, or in UTC
+// when tz is empty.
+func (d Datetime) InTimezone(tz string) (time.Time, error) {
+	location, err := time.LoadLocation(tz)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timezone %q: %w", tz, err)
+	}
+	return d.Time.In(location), nil
+}
+
+func (d Datetime) String() string {
+	if d.IsZero() {
+		return "false"
+	}
+	return d.UTC().Format(DateFormat)
+}
+
+func (d Datetime) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return json.Marshal(false)
+	}
+	return json.Marshal(d.UTC().Format(DateFormat))
+}
+
+func (d *Datetime) UnmarshalJSON(data []byte) error {
+	value, isSet, err := unmarshalOdooString(data)
+	if err != nil || !isSet {
+		*d = Datetime{}
+		return err
+	}
+	*d, err = ParseDatetime(value)
+	return err
+}
+
+// Date is the value of a date field, a calendar day without timezone. The zero value is
+// written as false.
+type Date struct {
+	time.Time // Midnight UTC
+}
+
+// NewDate returns the date of t in its own location, e.g. the day of a time in the
+// timezone of the user.
+func NewDate(t time.Time) Date {
+	return Date{time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)}
+}
+
+// ParseDate parses a date formatted with time.DateOnly, as returned by Odoo.
+func ParseDate(value string) (Date, error) {
+	parsed, err := time.ParseInLocation(time.DateOnly, value, time.UTC)
+	if err != nil {
+		return Date{}, fmt.Errorf("invalid date %q: %w", value, err)
+	}
+	return Date{parsed}, nil
+}
+
+func (d Date) String() string {
+	if d.IsZero() {
+		return "false"
+	}
+	return d.Format(time.DateOnly)
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return json.Marshal(false)
+	}
+	return json.Marshal(d.Format(time.DateOnly))
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	value, isSet, err := unmarshalOdooString(data)
+	if err != nil || !isSet {
+		*d = Date{}
+		return err
+	}
+	*d, err = ParseDate(value)
+	return err
+}
+
+// unmarshalOdooString decodes a string value, which Odoo returns as false when unset.
+func unmarshalOdooString(data []byte) (string, bool, error) {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return "", false, err
+	}
+	switch value := value.(type) {
+	case nil:
+		return "", false, nil
+	case bool:
+		if !value {
+			return "", false, nil
+		}
+	case string:
+		return value, value != "", nil
+	}
+	return "", false, fmt.Errorf("cannot decode %s as an Odoo date", data)
+}
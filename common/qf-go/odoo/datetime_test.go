@@ -0,0 +1,68 @@
+package odoo
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestDatetime(t *testing.T) {
+	toronto, err := time.LoadLocation("America/Toronto")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	local := time.Date(2025, 6, 10, 22, 30, 0, 0, toronto)
+
+	for _, value := range []any{NewDatetime(local), Datetime{local}} {
+		if encoded, _ := json.Marshal(value); string(encoded) != `"2025-06-11 02:30:00"` {
+			t.Fatalf("expected the datetime to be written in UTC, got %s", encoded)
+		}
+	}
+	if encoded, _ := json.Marshal(map[string]any{"date": Datetime{}, "day": Date{}}); string(encoded) != `{"date":false,"day":false}` {
+		t.Fatalf("expected zero values to be written as false, got %s", encoded)
+	}
+
+	var record struct {
+		Date  Datetime `json:"date"`
+		Unset Datetime `json:"unset"`
+	}
+	if err := json.Unmarshal([]byte(`{"date": "2025-06-11 02:30:00", "unset": false}`), &record); err != nil {
+		t.Fatal(err)
+	}
+	if !record.Date.Equal(local) || record.Date.Location() != time.UTC || !record.Unset.IsZero() {
+		t.Fatalf("unexpected decoded datetimes %v, %v", record.Date, record.Unset)
+	}
+	if inToronto, err := record.Date.InTimezone("America/Toronto"); err != nil || inToronto.Hour() != 22 || inToronto.Day() != 10 {
+		t.Fatalf("expected the datetime in the user timezone, got %v, %v", inToronto, err)
+	}
+	if _, err := record.Date.InTimezone("Nowhere/Town"); err == nil {
+		t.Fatalf("expected error for an invalid timezone")
+	}
+	if err := json.Unmarshal([]byte(`{"date": "June 11"}`), &record); err == nil {
+		t.Fatalf("expected error for an invalid datetime")
+	}
+}
+
+func TestDate(t *testing.T) {
+	toronto, err := time.LoadLocation("America/Toronto")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	// Still June 10 for the user, though June 11 in UTC
+	date := NewDate(time.Date(2025, 6, 10, 22, 30, 0, 0, toronto))
+	if encoded, _ := json.Marshal(date); string(encoded) != `"2025-06-10"` {
+		t.Fatalf("expected the day of the user, got %s", encoded)
+	}
+
+	type record struct {
+		Day   Date `odoo:"day"`
+		Unset Date `odoo:"unset"`
+	}
+	decoded, err := DecodeRecord[record](map[string]any{"day": "2025-06-10", "unset": false})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Day != date || !decoded.Unset.IsZero() {
+		t.Fatalf("unexpected decoded dates %v, %v", decoded.Day, decoded.Unset)
+	}
+}
@@ -0,0 +1,238 @@
+package odoo
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+)
+
+// Many2One is the [id, display_name] pair Odoo returns for many2one fields.
+type Many2One struct {
+	ID   int
+	Name string
+}
+
+func (m Many2One) MarshalJSON() ([]byte, error) {
+	if m.ID == 0 {
+		return json.Marshal(false)
+	}
+	return json.Marshal(m.ID)
+}
+
+var (
+	many2OneType      = reflect.TypeFor[Many2One]()
+	timeType          = reflect.TypeFor[time.Time]()
+	jsonUnmarshalType = reflect.TypeFor[json.Unmarshaler]()
+)
+
+// FieldsOf returns the Odoo field names declared with `odoo:"..."` tags on struct T.
+// Untagged fields and fields tagged with "-" are ignored.
+func FieldsOf[T any]() []string {
+	fields := []string{}
+	structType := reflect.TypeFor[T]()
+	if structType.Kind() != reflect.Struct {
+		return fields
+	}
+	for i := range structType.NumField() {
+		field := structType.Field(i)
+		name := field.Tag.Get("odoo")
+		if name != "" && name != "-" && field.IsExported() {
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
+
+// DecodeRecord decodes a record as returned by search_read or read into struct T,
+// using the `odoo:"..."` tag of each field. Odoo's false is decoded as the zero value
+// (or nil for pointers), many2one pairs into Many2One or into their ID for int
+// fields, and datetimes formatted with DateFormat into time.Time (UTC).
+func DecodeRecord[T any](record map[string]any) (T, error) {
+	var result T
+	structValue := reflect.ValueOf(&result).Elem()
+	if structValue.Kind() != reflect.Struct {
+		return result, fmt.Errorf("cannot decode Odoo record into %T, expected struct", result)
+	}
+	structType := structValue.Type()
+	for i := range structType.NumField() {
+		field := structType.Field(i)
+		name := field.Tag.Get("odoo")
+		if name == "" || name == "-" || !field.IsExported() {
+			continue
+		}
+		value, found := record[name]
+		if !found {
+			continue
+		}
+		if err := decodeValue(value, structValue.Field(i)); err != nil {
+			return result, fmt.Errorf("error decoding field %v into %v.%v: %w", name, structType.Name(), field.Name, err)
+		}
+	}
+	return result, nil
+}
+
+func decodeRecords[T any](records []map[string]any) ([]T, error) {
+	result := make([]T, len(records))
+	for i, record := range records {
+		decoded, err := DecodeRecord[T](record)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = decoded
+	}
+	return result, nil
+}
+
+func decodeValue(value any, target reflect.Value) error {
+	if value == nil || value == false && target.Kind() != reflect.Bool && target.Kind() != reflect.Interface {
+		target.SetZero()
+		return nil
+	}
+
+	switch target.Type() {
+	case many2OneType:
+		pair, ok := value.([]any)
+		if !ok || len(pair) != 2 {
+			return mismatch(value, target)
+		}
+		id, okId := pair[0].(float64)
+		name, okName := pair[1].(string)
+		if !okId || !okName {
+			return mismatch(value, target)
+		}
+		target.Set(reflect.ValueOf(Many2One{ID: int(id), Name: name}))
+		return nil
+	case timeType:
+		valueStr, ok := value.(string)
+		if !ok {
+			return mismatch(value, target)
+		}
+		parsed, err := time.ParseInLocation(DateFormat, valueStr, time.UTC)
+		if err != nil {
+			parsed, err = time.ParseInLocation(time.DateOnly, valueStr, time.UTC)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid date %q: %w", valueStr, err)
+		}
+		target.Set(reflect.ValueOf(parsed))
+		return nil
+	}
+
+	if target.Kind() != reflect.Pointer && target.Kind() != reflect.Interface && target.Addr().Type().Implements(jsonUnmarshalType) {
+		valueJson, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		return target.Addr().Interface().(json.Unmarshaler).UnmarshalJSON(valueJson)
+	}
+
+	switch target.Kind() {
+	case reflect.Pointer:
+		elem := reflect.New(target.Type().Elem())
+		if err := decodeValue(value, elem.Elem()); err != nil {
+			return err
+		}
+		target.Set(elem)
+		return nil
+	case reflect.Interface:
+		if !reflect.TypeOf(value).AssignableTo(target.Type()) {
+			return mismatch(value, target)
+		}
+		target.Set(reflect.ValueOf(value))
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		number, ok := value.(float64)
+		if pair, isPair := value.([]any); isPair && len(pair) == 2 {
+			// Many2one decoded as its ID
+			number, ok = pair[0].(float64)
+		}
+		if !ok || number != math.Trunc(number) {
+			return mismatch(value, target)
+		}
+		target.SetInt(int64(number))
+		return nil
+	case reflect.Float32, reflect.Float64:
+		number, ok := value.(float64)
+		if !ok {
+			return mismatch(value, target)
+		}
+		target.SetFloat(number)
+		return nil
+	case reflect.String:
+		valueStr, ok := value.(string)
+		if !ok {
+			return mismatch(value, target)
+		}
+		target.SetString(valueStr)
+		return nil
+	case reflect.Bool:
+		valueBool, ok := value.(bool)
+		if !ok {
+			return mismatch(value, target)
+		}
+		target.SetBool(valueBool)
+		return nil
+	case reflect.Slice:
+		list, ok := value.([]any)
+		if !ok {
+			return mismatch(value, target)
+		}
+		slice := reflect.MakeSlice(target.Type(), len(list), len(list))
+		for i, item := range list {
+			if err := decodeValue(item, slice.Index(i)); err != nil {
+				return fmt.Errorf("element %d: %w", i, err)
+			}
+		}
+		target.Set(slice)
+		return nil
+	case reflect.Map:
+		valueMap, ok := value.(map[string]any)
+		if !ok || !reflect.TypeOf(valueMap).AssignableTo(target.Type()) {
+			return mismatch(value, target)
+		}
+		target.Set(reflect.ValueOf(valueMap))
+		return nil
+	}
+	return mismatch(value, target)
+}
+
+func mismatch(value any, target reflect.Value) error {
+	return fmt.Errorf("cannot decode %T (%v) into %v", value, value, target.Type())
+}
+
+// SearchReadInto searches model and decodes the results into T, reading only the
+// fields declared in T's `odoo:"..."` tags. A nil client uses Default.
+func SearchReadInto[T any](c *Client, model string, domain []any, limit int, context map[string]any) ([]T, error) {
+	c, err := orDefault(c)
+	if err != nil {
+		return nil, err
+	}
+	records, err := c.SearchRead(model, domain, FieldsOf[T](), limit, context)
+	if err != nil {
+		return nil, err
+	}
+	return decodeRecords[T](records)
+}
+
+// ReadInto reads the records with the given ids and decodes them into T, reading
+// only the fields declared in T's `odoo:"..."` tags. A nil client uses Default.
+func ReadInto[T any](c *Client, model string, ids []int, context map[string]any) ([]T, error) {
+	c, err := orDefault(c)
+	if err != nil {
+		return nil, err
+	}
+	records, err := c.Read(model, ids, FieldsOf[T](), context)
+	if err != nil {
+		return nil, err
+	}
+	return decodeRecords[T](records)
+}
+
+func orDefault(c *Client) (*Client, error) {
+	if c != nil {
+		return c, nil
+	}
+	return Default()
+}
@@ -0,0 +1,118 @@
+package odoo
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type decodeTestRecord struct {
+	ID        int            `odoo:"id"`
+	Name      string         `odoo:"name"`
+	Amount    float64        `odoo:"amount"`
+	Active    bool           `odoo:"active"`
+	Company   Many2One       `odoo:"company_id"`
+	Partner   *Many2One      `odoo:"partner_id"`
+	UserID    int            `odoo:"user_id"`
+	TagIds    []int          `odoo:"tag_ids"`
+	Date      time.Time      `odoo:"date_order"`
+	Note      *string        `odoo:"note"`
+	Extra     map[string]any `odoo:"extra"`
+	Ignored   string         `odoo:"-"`
+	NotMapped string
+}
+
+func TestFieldsOf(t *testing.T) {
+	expected := []string{"id", "name", "amount", "active", "company_id", "partner_id", "user_id", "tag_ids", "date_order", "note", "extra"}
+	if fields := FieldsOf[decodeTestRecord](); !reflect.DeepEqual(fields, expected) {
+		t.Fatalf("expected %v, got %v", expected, fields)
+	}
+}
+
+func TestDecodeRecord(t *testing.T) {
+	record, err := DecodeRecord[decodeTestRecord](map[string]any{
+		"id":         12.0,
+		"name":       "SO012",
+		"amount":     10.5,
+		"active":     true,
+		"company_id": []any{2.0, "QF"},
+		"partner_id": false,
+		"user_id":    []any{6.0, "Admin"},
+		"tag_ids":    []any{1.0, 2.0},
+		"date_order": "2025-06-15 16:26:59",
+		"note":       false,
+		"extra":      map[string]any{"a": 1.0},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := decodeTestRecord{
+		ID:      12,
+		Name:    "SO012",
+		Amount:  10.5,
+		Active:  true,
+		Company: Many2One{ID: 2, Name: "QF"},
+		UserID:  6,
+		TagIds:  []int{1, 2},
+		Date:    time.Date(2025, 6, 15, 16, 26, 59, 0, time.UTC),
+		Extra:   map[string]any{"a": 1.0},
+	}
+	if !reflect.DeepEqual(record, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, record)
+	}
+}
+
+func TestDecodeRecord_FalseValues(t *testing.T) {
+	record, err := DecodeRecord[decodeTestRecord](map[string]any{
+		"name":       false,
+		"company_id": false,
+		"date_order": false,
+		"tag_ids":    false,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(record, decodeTestRecord{}) {
+		t.Fatalf("expected zero values, got %+v", record)
+	}
+}
+
+func TestDecodeRecord_Mismatch(t *testing.T) {
+	tests := []struct {
+		Title  string
+		Record map[string]any
+	}{
+		{Title: "String into int", Record: map[string]any{"id": "12"}},
+		{Title: "Decimal into int", Record: map[string]any{"id": 1.5}},
+		{Title: "Number into many2one", Record: map[string]any{"company_id": 2.0}},
+		{Title: "Invalid date", Record: map[string]any{"date_order": "yesterday"}},
+		{Title: "Invalid list element", Record: map[string]any{"tag_ids": []any{"a"}}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.Title, func(t *testing.T) {
+			_, err := DecodeRecord[decodeTestRecord](tc.Record)
+			if err == nil || !strings.Contains(err.Error(), "error decoding field") {
+				t.Fatalf("expected decoding error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestSearchReadInto(t *testing.T) {
+	requests := []rpcRequest{}
+	client := newTestClient(t, &requests, func(rpcRequest) (int, any) {
+		return resultResponse([]any{map[string]any{"id": 1.0, "name": "A", "company_id": []any{3.0, "FM"}}})
+	})
+	records, err := SearchReadInto[decodeTestRecord](client, "sale.order", []any{}, 0, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(records) != 1 || records[0].Company.ID != 3 || records[0].Name != "A" {
+		t.Fatalf("unexpected records: %+v", records)
+	}
+	fields := requests[0].Params["args"].([]any)[6].(map[string]any)["fields"].([]any)
+	if len(fields) != len(FieldsOf[decodeTestRecord]()) {
+		t.Fatalf("expected fields from struct tags to be requested, got %v", fields)
+	}
+}
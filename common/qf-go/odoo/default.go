@@ -0,0 +1,308 @@
+package odoo
+
+// Package-level shortcuts over the Default client.
+
+func JsonRpcExecuteKw(model, method string, args []any, kwargs map[string]any) (any, error) {
+	c, err := Default()
+	if err != nil {
+		return nil, err
+	}
+	return c.JsonRpcExecuteKw(model, method, args, kwargs)
+}
+
+func SearchRead(model string, domain []any, fields []string, limit int, context map[string]any) ([]map[string]any, error) {
+	c, err := Default()
+	if err != nil {
+		return nil, err
+	}
+	return c.SearchRead(model, domain, fields, limit, context)
+}
+
+func Read(model string, ids []int, fields []string, context map[string]any) ([]map[string]any, error) {
+	c, err := Default()
+	if err != nil {
+		return nil, err
+	}
+	return c.Read(model, ids, fields, context)
+}
+
+func SearchReadWithOptions(model string, domain []any, fields []string, options SearchOptions) ([]map[string]any, error) {
+	c, err := Default()
+	if err != nil {
+		return nil, err
+	}
+	return c.SearchReadWithOptions(model, domain, fields, options)
+}
+
+func SearchCount(model string, domain []any, context map[string]any) (int, error) {
+	c, err := Default()
+	if err != nil {
+		return 0, err
+	}
+	return c.SearchCount(model, domain, context)
+}
+
+func SearchReadOne(model string, domain []any, fields []string, context map[string]any) (map[string]any, error) {
+	c, err := Default()
+	if err != nil {
+		return nil, err
+	}
+	return c.SearchReadOne(model, domain, fields, context)
+}
+
+func SearchReadById(model string, id int, fields []string, context map[string]any) (map[string]any, error) {
+	c, err := Default()
+	if err != nil {
+		return nil, err
+	}
+	return c.SearchReadById(model, id, fields, context)
+}
+
+func ReadRecordByXID(model string, xid string, fields []string) (map[string]any, error) {
+	c, err := Default()
+	if err != nil {
+		return nil, err
+	}
+	return c.ReadRecordByXID(model, xid, fields)
+}
+
+func GetIDByXID(model string, xid string) (int, error) {
+	c, err := Default()
+	if err != nil {
+		return 0, err
+	}
+	return c.GetIDByXID(model, xid)
+}
+
+func GetIDsByXIDs(model string, xids []string) (map[string]int, error) {
+	c, err := Default()
+	if err != nil {
+		return nil, err
+	}
+	return c.GetIDsByXIDs(model, xids)
+}
+
+func AssignRecordXID(model string, id int, xid string) error {
+	c, err := Default()
+	if err != nil {
+		return err
+	}
+	return c.AssignRecordXID(model, id, xid)
+}
+
+func CreateOrGetByXID(model string, xid string, data map[string]any, context map[string]any) (int, bool, error) {
+	c, err := Default()
+	if err != nil {
+		return 0, false, err
+	}
+	return c.CreateOrGetByXID(model, xid, data, context)
+}
+
+func CreateMulti(model string, data []map[string]any, context map[string]any) ([]int, error) {
+	c, err := Default()
+	if err != nil {
+		return []int{}, err
+	}
+	return c.CreateMulti(model, data, context)
+}
+
+func Create(model string, data map[string]any, context map[string]any) (int, error) {
+	c, err := Default()
+	if err != nil {
+		return 0, err
+	}
+	return c.Create(model, data, context)
+}
+
+func WriteMulti(model string, ids []int, data map[string]any, context map[string]any) error {
+	c, err := Default()
+	if err != nil {
+		return err
+	}
+	return c.WriteMulti(model, ids, data, context)
+}
+
+func Write(model string, id int, data map[string]any, context map[string]any) error {
+	c, err := Default()
+	if err != nil {
+		return err
+	}
+	return c.Write(model, id, data, context)
+}
+
+func SearchIds(model string, domain []any, context map[string]any) ([]int, error) {
+	c, err := Default()
+	if err != nil {
+		return []int{}, err
+	}
+	return c.SearchIds(model, domain, context)
+}
+
+func SearchId(model string, domain []any, context map[string]any) (int, error) {
+	c, err := Default()
+	if err != nil {
+		return 0, err
+	}
+	return c.SearchId(model, domain, context)
+}
+
+func SearchFirstId(model string, domain []any, context map[string]any) (int, error) {
+	c, err := Default()
+	if err != nil {
+		return 0, err
+	}
+	return c.SearchFirstId(model, domain, context)
+}
+
+func SearchWrite(model string, domain []any, data map[string]any, context map[string]any) error {
+	c, err := Default()
+	if err != nil {
+		return err
+	}
+	return c.SearchWrite(model, domain, data, context)
+}
+
+func SearchWriteOne(model string, domain []any, data map[string]any, context map[string]any) error {
+	c, err := Default()
+	if err != nil {
+		return err
+	}
+	return c.SearchWriteOne(model, domain, data, context)
+}
+
+func FindOrCreate(model string, domain []any, createData map[string]any, context map[string]any) (int, error) {
+	c, err := Default()
+	if err != nil {
+		return 0, err
+	}
+	return c.FindOrCreate(model, domain, createData, context)
+}
+
+func FindFirstOrCreate(model string, domain []any, createData map[string]any, context map[string]any) (int, error) {
+	c, err := Default()
+	if err != nil {
+		return 0, err
+	}
+	return c.FindFirstOrCreate(model, domain, createData, context)
+}
+
+func WriteOrCreate(model string, domain []any, data map[string]any, writeOnlyData map[string]any, createOnlyData map[string]any, context map[string]any) (int, error) {
+	c, err := Default()
+	if err != nil {
+		return 0, err
+	}
+	return c.WriteOrCreate(model, domain, data, writeOnlyData, createOnlyData, context)
+}
+
+func UnlinkMulti(model string, ids []int, context map[string]any) error {
+	c, err := Default()
+	if err != nil {
+		return err
+	}
+	return c.UnlinkMulti(model, ids, context)
+}
+
+func Unlink(model string, id int, context map[string]any) error {
+	c, err := Default()
+	if err != nil {
+		return err
+	}
+	return c.Unlink(model, id, context)
+}
+
+func ResolveCountryState(country string, state string) (countryId int, stateId int, err error) {
+	c, err := Default()
+	if err != nil {
+		return 0, 0, err
+	}
+	return c.ResolveCountryState(country, state)
+}
+
+// Deprecated: use ResolveCountryState, which returns why.
+func GetCountryAndStateIds(countryCode string, stateCode string) (int, int) {
+	c, err := Default()
+	if err != nil {
+		return 0, 0
+	}
+	return c.GetCountryAndStateIds(countryCode, stateCode)
+}
+
+func ReadGroup(model string, domain []any, fields []string, groupby []string, options ReadGroupOptions) ([]Group, error) {
+	c, err := Default()
+	if err != nil {
+		return nil, err
+	}
+	return c.ReadGroup(model, domain, fields, groupby, options)
+}
+
+func FieldsGet(model string) (map[string]Field, error) {
+	c, err := Default()
+	if err != nil {
+		return nil, err
+	}
+	return c.FieldsGet(model)
+}
+
+func ServerVersion() (Version, error) {
+	c, err := Default()
+	if err != nil {
+		return Version{}, err
+	}
+	return c.ServerVersion()
+}
+
+func ModelName(model string) (string, error) {
+	c, err := Default()
+	if err != nil {
+		return "", err
+	}
+	return c.ModelName(model)
+}
+
+func FieldName(model string, field string) (string, error) {
+	c, err := Default()
+	if err != nil {
+		return "", err
+	}
+	return c.FieldName(model, field)
+}
+
+func CompatValues(model string, values map[string]any) (map[string]any, error) {
+	c, err := Default()
+	if err != nil {
+		return nil, err
+	}
+	return c.CompatValues(model, values)
+}
+
+func MessagePost(model string, id int, message Message) (int, error) {
+	c, err := Default()
+	if err != nil {
+		return 0, err
+	}
+	return c.MessagePost(model, id, message)
+}
+
+func Attach(model string, id int, name string, content []byte, mimetype string) (int, error) {
+	c, err := Default()
+	if err != nil {
+		return 0, err
+	}
+	return c.Attach(model, id, name, content, mimetype)
+}
+
+func SoftDeleteMulti(model string, ids []int, context map[string]any) error {
+	c, err := Default()
+	if err != nil {
+		return err
+	}
+	return c.SoftDeleteMulti(model, ids, context)
+}
+
+func SoftDelete(model string, id int, context map[string]any) error {
+	c, err := Default()
+	if err != nil {
+		return err
+	}
+	return c.SoftDelete(model, id, context)
+}
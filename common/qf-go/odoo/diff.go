@@ -0,0 +1,208 @@
+package odoo
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"qf/go/helpers"
+	"reflect"
+	"slices"
+)
+
+// Record is a record as read from Odoo, or the values to write to one.
+type Record map[string]any
+
+// DiffOne2Many returns the commands turning the existing records of a one2many field into
+// the desired ones, in a single write. Records are matched with keyFn, records with a nil
+// or empty key are never matched:
+//   - matched records are updated with the values that changed only, and skipped if none did
+//   - desired records not matched are created, or updated when they have the "id" of an
+//     existing record
+//   - existing records not matched are deleted
+//
+// A desired "id" not among the existing records is an error, linking it would move the
+// record of another parent into this one. Existing records must have an "id" and the
+// desired fields as returned by read, e.g. many2one pairs, which are compared to the IDs
+// or commands written.
+func DiffOne2Many(existing []Record, desired []Record, keyFn func(Record) any) ([]any, error) {
+	commands := []any{}
+	existingByKey := map[any]Record{}
+	existingIds := map[int]bool{}
+	for _, record := range existing {
+		existingIds[recordId(record["id"])] = true
+		if key := diffKey(keyFn(record)); !emptyKey(key) {
+			existingByKey[key] = record
+		}
+	}
+
+	matchedIds := map[int]bool{}
+	for _, record := range desired {
+		values := maps.Clone(record)
+		delete(values, "id")
+		key := diffKey(keyFn(record))
+		found, isFound := existingByKey[key]
+		if emptyKey(key) || !isFound {
+			if id := recordId(record["id"]); id != 0 {
+				if !existingIds[id] {
+					return nil, fmt.Errorf("record %v is not among the existing records of the one2many field", id)
+				}
+				if len(values) != 0 {
+					commands = append(commands, Command.Update(id, values))
+				}
+				matchedIds[id] = true
+			} else {
+				commands = append(commands, Command.Create(values))
+			}
+			continue
+		}
+		id := recordId(found["id"])
+		matchedIds[id] = true
+		delete(existingByKey, key)
+		changed := map[string]any{}
+		for field, value := range values {
+			if current, read := found[field]; !read || !valuesEqual(current, value) {
+				changed[field] = value
+			}
+		}
+		if len(changed) != 0 {
+			commands = append(commands, Command.Update(id, changed))
+		}
+	}
+
+	for _, record := range existing {
+		if id := recordId(record["id"]); !matchedIds[id] {
+			commands = append(commands, Command.Delete(id))
+		}
+	}
+	return commands, nil
+}
+
+// ByID is a DiffOne2Many key matching records by "id", for desired records already
+// resolved, e.g. through their XID.
+func ByID(record Record) any {
+	return recordId(record["id"])
+}
+
+// DiffMany2Many returns the link and unlink commands turning the existing IDs of a
+// many2many field into the desired ones, without deleting the records unlinked.
+func DiffMany2Many(existing []int, desired []int) []any {
+	commands := []any{}
+	for _, id := range desired {
+		if !slices.Contains(existing, id) {
+			commands = append(commands, Command.Link(id))
+		}
+	}
+	for _, id := range existing {
+		if !slices.Contains(desired, id) {
+			commands = append(commands, Command.Unlink(id))
+		}
+	}
+	return commands
+}
+
+func recordId(value any) int {
+	if id, isInt := value.(int); isInt {
+		return id
+	}
+	return helpers.JsonInt(value)
+}
+
+// diffKey makes a DiffOne2Many key usable in a map: many2one pairs as read become their
+// ID, matching the ID written, and other keys that are not comparable their JSON form.
+func diffKey(key any) any {
+	if pair, isPair := key.([]any); isPair && len(pair) == 2 {
+		if _, isId := pair[0].(float64); isId {
+			return recordId(pair[0])
+		}
+	}
+	if key == nil || reflect.ValueOf(key).Comparable() {
+		return key
+	}
+	content, err := json.Marshal(key)
+	if err != nil {
+		return fmt.Sprint(key)
+	}
+	return string(content)
+}
+
+func emptyKey(key any) bool {
+	return key == nil || key == "" || key == 0
+}
+
+// valuesEqual compares a value as read from Odoo with a value to write.
+func valuesEqual(current any, value any) bool {
+	normalized, err := normalizeJson(value)
+	if err != nil {
+		return false
+	}
+	switch normalized := normalized.(type) {
+	case nil, bool:
+		if current == false || current == nil {
+			return normalized == nil || normalized == false
+		}
+	case string:
+		if current == false {
+			return normalized == ""
+		}
+	case float64:
+		if pair, isPair := current.([]any); isPair && len(pair) == 2 {
+			// many2one
+			return helpers.JsonInt(pair[0]) == int(normalized)
+		}
+		if current == false {
+			return normalized == 0
+		}
+	case []any:
+		if ids, isIds := currentIds(current); isIds {
+			if desiredIds, isSet := setCommandIds(normalized); isSet {
+				slices.Sort(ids)
+				slices.Sort(desiredIds)
+				return slices.Equal(ids, desiredIds)
+			}
+			return false
+		}
+	}
+	return reflect.DeepEqual(current, normalized)
+}
+
+// normalizeJson converts a value to its JSON decoded form, e.g. numbers as float64.
+func normalizeJson(value any) (any, error) {
+	content, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var normalized any
+	err = json.Unmarshal(content, &normalized)
+	return normalized, err
+}
+
+// currentIds returns the IDs of an x2many value as read.
+func currentIds(current any) ([]int, bool) {
+	if current == false {
+		return []int{}, true
+	}
+	list, isList := current.([]any)
+	if !isList {
+		return nil, false
+	}
+	ids := make([]int, len(list))
+	for i, id := range list {
+		if _, isNumber := id.(float64); !isNumber {
+			return nil, false
+		}
+		ids[i] = helpers.JsonInt(id)
+	}
+	return ids, true
+}
+
+// setCommandIds returns the IDs of a list holding a single Command.Set.
+func setCommandIds(commands []any) ([]int, bool) {
+	if len(commands) != 1 {
+		return nil, false
+	}
+	command, isCommand := commands[0].([]any)
+	if !isCommand || len(command) != 3 || command[0] != 6.0 {
+		return nil, false
+	}
+	return currentIds(command[2])
+}
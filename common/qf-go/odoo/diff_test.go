@@ -0,0 +1,59 @@
+package odoo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffOne2Many(t *testing.T) {
+	existing := []Record{
+		{"id": 1.0, "name": "Unchanged", "product_id": []any{10.0, "Saffron"}, "price_unit": 25.0, "tax_id": []any{5.0, 4.0}, "note": false},
+		{"id": 2.0, "name": "Changed", "product_id": []any{11.0, "Vanilla"}, "price_unit": 40.0, "tax_id": []any{}},
+		{"id": 3.0, "name": "Removed"},
+	}
+	desired := []Record{
+		{"id": 1, "name": "Unchanged", "product_id": 10, "price_unit": 25, "tax_id": []any{Command.Set([]int{4, 5})}, "note": ""},
+		{"id": 2, "name": "Changed", "product_id": 11, "price_unit": 42.5, "tax_id": []any{Command.Set([]int{4})}},
+		{"name": "New", "product_id": 12},
+	}
+	commands, err := DiffOne2Many(existing, desired, ByID)
+	expected := []any{
+		Command.Update(2, map[string]any{"price_unit": 42.5, "tax_id": []any{Command.Set([]int{4})}}),
+		Command.Create(map[string]any{"name": "New", "product_id": 12}),
+		Command.Delete(3),
+	}
+	if err != nil || !reflect.DeepEqual(commands, expected) {
+		t.Fatalf("expected %v, got %v, %v", expected, commands, err)
+	}
+
+	// A record of another parent would be moved into this one
+	if _, err := DiffOne2Many(existing, append(desired, Record{"id": 9, "name": "Moved"}), ByID); err == nil {
+		t.Fatalf("expected error for a record not among the existing ones")
+	}
+
+	byName := func(record Record) any { return record["name"] }
+	if commands, _ := DiffOne2Many(existing[:1], []Record{{"name": "Unchanged", "price_unit": 25}}, byName); len(commands) != 0 {
+		t.Fatalf("expected no command for unchanged records, got %v", commands)
+	}
+
+	// many2one pairs as read match the IDs written
+	byProduct := func(record Record) any { return record["product_id"] }
+	commands, _ = DiffOne2Many(existing[:2], []Record{{"product_id": 11, "price_unit": 40}, {"product_id": 10, "price_unit": 30}}, byProduct)
+	expected = []any{Command.Update(1, map[string]any{"price_unit": 30})}
+	if !reflect.DeepEqual(commands, expected) {
+		t.Fatalf("expected %v, got %v", expected, commands)
+	}
+	// Other keys that are not comparable are matched by value
+	byNameAndPrice := func(record Record) any { return []any{record["name"], record["price_unit"]} }
+	if commands, _ := DiffOne2Many(existing[:1], []Record{{"name": "Unchanged", "price_unit": 25}}, byNameAndPrice); len(commands) != 0 {
+		t.Fatalf("expected no command for unchanged records, got %v", commands)
+	}
+}
+
+func TestDiffMany2Many(t *testing.T) {
+	commands := DiffMany2Many([]int{1, 2, 3}, []int{3, 4})
+	expected := []any{Command.Link(4), Command.Unlink(1), Command.Unlink(2)}
+	if !reflect.DeepEqual(commands, expected) {
+		t.Fatalf("expected %v, got %v", expected, commands)
+	}
+}
@@ -0,0 +1,236 @@
+package odoo
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+type Operator string
+
+const (
+	OpEq        Operator = "="
+	OpNe        Operator = "!="
+	OpGt        Operator = ">"
+	OpGe        Operator = ">="
+	OpLt        Operator = "<"
+	OpLe        Operator = "<="
+	OpEqOrUnset Operator = "=?"
+	OpLike      Operator = "like"
+	OpNotLike   Operator = "not like"
+	OpILike     Operator = "ilike"
+	OpNotILike  Operator = "not ilike"
+	OpEqLike    Operator = "=like"
+	OpEqILike   Operator = "=ilike"
+	OpIn        Operator = "in"
+	OpNotIn     Operator = "not in"
+	OpChildOf   Operator = "child_of"
+	OpParentOf  Operator = "parent_of"
+)
+
+var operators = []Operator{
+	OpEq, OpNe, OpGt, OpGe, OpLt, OpLe, OpEqOrUnset,
+	OpLike, OpNotLike, OpILike, OpNotILike, OpEqLike, OpEqILike,
+	OpIn, OpNotIn, OpChildOf, OpParentOf,
+}
+
+const (
+	domainAnd = "&"
+	domainOr  = "|"
+	domainNot = "!"
+)
+
+// Domain is an Odoo search domain in Polish (prefix) notation. It can be passed
+// anywhere a []any domain is expected, and existing []any literals can be converted
+// with Domain(literal) to combine them with And, Or and Not.
+type Domain []any
+
+// Term is a value for MapToDomain that carries its own operator.
+type Term struct {
+	Operator Operator
+	Value    any
+}
+
+// Cond returns a domain with the single condition (field, op, value).
+func Cond(field string, op Operator, value any) Domain {
+	return Domain{[]any{field, string(op), value}}
+}
+
+// And combines domains so all of them must match. Empty domains match everything and
+// are skipped.
+func And(domains ...Domain) Domain {
+	return combine(domainAnd, domains, false)
+}
+
+// Or combines domains so any of them must match. An empty domain matches everything,
+// and so does the result.
+func Or(domains ...Domain) Domain {
+	return combine(domainOr, domains, true)
+}
+
+// Not negates domain.
+func Not(domain Domain) Domain {
+	normalized := domain.normalize()
+	if len(normalized) == 0 {
+		return Domain{[]any{0, "=", 1}}
+	}
+	return append(Domain{domainNot}, normalized...)
+}
+
+func combine(operator string, domains []Domain, emptyMatchesAll bool) Domain {
+	parts := make([]Domain, 0, len(domains))
+	for _, domain := range domains {
+		normalized := domain.normalize()
+		if len(normalized) == 0 {
+			if emptyMatchesAll {
+				return Domain{}
+			}
+			continue
+		}
+		parts = append(parts, normalized)
+	}
+	result := Domain{}
+	for range max(len(parts)-1, 0) {
+		result = append(result, operator)
+	}
+	for _, part := range parts {
+		result = append(result, part...)
+	}
+	return result
+}
+
+// normalize makes the implicit "&" between top-level expressions explicit, so the
+// domain can be nested inside another one.
+func (d Domain) normalize() Domain {
+	count, err := d.expressions()
+	if err != nil || count <= 1 {
+		return d
+	}
+	normalized := make(Domain, 0, len(d)+count-1)
+	for range count - 1 {
+		normalized = append(normalized, domainAnd)
+	}
+	return append(normalized, d...)
+}
+
+// expressions returns the number of top-level expressions of the domain.
+func (d Domain) expressions() (int, error) {
+	count := 0
+	for i := 0; i < len(d); count++ {
+		next, err := d.parse(i)
+		if err != nil {
+			return 0, err
+		}
+		i = next
+	}
+	return count, nil
+}
+
+// parse reads the expression starting at index i and returns the index after it.
+func (d Domain) parse(i int) (int, error) {
+	if i >= len(d) {
+		return 0, fmt.Errorf("domain operator at the end is missing its operands: %v", d)
+	}
+	switch d[i] {
+	case domainAnd, domainOr:
+		next, err := d.parse(i + 1)
+		if err != nil {
+			return 0, err
+		}
+		return d.parse(next)
+	case domainNot:
+		return d.parse(i + 1)
+	}
+	if err := validateLeaf(d[i]); err != nil {
+		return 0, err
+	}
+	return i + 1, nil
+}
+
+func validateLeaf(leaf any) error {
+	rLeaf := reflect.ValueOf(leaf)
+	if rLeaf.Kind() != reflect.Slice && rLeaf.Kind() != reflect.Array {
+		return fmt.Errorf("invalid domain term %v: expected operator or condition, got %T", leaf, leaf)
+	}
+	if rLeaf.Len() != 3 {
+		return fmt.Errorf("invalid domain condition %v: expected 3 elements, got %d", leaf, rLeaf.Len())
+	}
+	field := rLeaf.Index(0).Interface()
+	operator := rLeaf.Index(1).Interface()
+	value := rLeaf.Index(2).Interface()
+	if fieldInt, ok := field.(int); ok && (fieldInt == 0 || fieldInt == 1) && operator == "=" && value == 1 {
+		// TRUE_LEAF or FALSE_LEAF
+		return nil
+	}
+	if fieldStr, ok := field.(string); !ok || fieldStr == "" {
+		return fmt.Errorf("invalid domain condition %v: field must be a non-empty string", leaf)
+	}
+	var op Operator
+	switch operator := operator.(type) {
+	case string:
+		op = Operator(operator)
+	case Operator:
+		op = operator
+	}
+	if !slices.Contains(operators, op) {
+		return fmt.Errorf("invalid domain condition %v: unknown operator %v", leaf, operator)
+	}
+	if op == OpIn || op == OpNotIn {
+		kind := reflect.ValueOf(value).Kind()
+		if kind != reflect.Slice && kind != reflect.Array {
+			return fmt.Errorf("invalid domain condition %v: operator %v expects a list, got %T", leaf, op, value)
+		}
+	}
+	return nil
+}
+
+// Validate checks that the domain is well-formed Polish notation with known operators.
+func (d Domain) Validate() error {
+	_, err := d.expressions()
+	return err
+}
+
+// MapToDomain builds a domain matching every key of dataMap, in key order. Strings are
+// matched case-insensitively (=ilike), slices with "in", numbers, booleans and nil
+// with "=". Use a Term value to choose the operator explicitly.
+func MapToDomain(dataMap map[string]any) (Domain, error) {
+	keys := make([]string, 0, len(dataMap))
+	for key := range dataMap {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	conditions := make([]Domain, 0, len(keys))
+	for _, key := range keys {
+		val := dataMap[key]
+		if term, ok := val.(Term); ok {
+			conditions = append(conditions, Cond(key, term.Operator, term.Value))
+			continue
+		}
+		if val == nil {
+			conditions = append(conditions, Cond(key, OpEq, false))
+			continue
+		}
+		switch reflect.ValueOf(val).Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64, reflect.Bool:
+			conditions = append(conditions, Cond(key, OpEq, val))
+		case reflect.String:
+			conditions = append(conditions, Cond(key, OpEqILike, val))
+		case reflect.Slice, reflect.Array:
+			conditions = append(conditions, Cond(key, OpIn, val))
+		default:
+			return nil, fmt.Errorf("unsupported value type %T for field %v in domain", val, key)
+		}
+	}
+
+	domain := Domain{}
+	for _, condition := range conditions {
+		domain = append(domain, condition...)
+	}
+	if err := domain.Validate(); err != nil {
+		return nil, err
+	}
+	return domain, nil
+}
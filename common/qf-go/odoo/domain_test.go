@@ -0,0 +1,58 @@
+package odoo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDomain_Combine(t *testing.T) {
+	a := Cond("a", OpEq, 1)
+	b := Cond("b", OpIn, []int{1, 2})
+	c := Cond("c", OpILike, "x")
+	implicit := Domain{[]any{"d", "=", 1}, []any{"e", "=", 2}}
+	tests := []struct {
+		Title    string
+		Domain   Domain
+		Expected Domain
+	}{
+		{Title: "And", Domain: And(a, b, c), Expected: Domain{"&", "&", a[0], b[0], c[0]}},
+		{Title: "Or", Domain: Or(a, b), Expected: Domain{"|", a[0], b[0]}},
+		{Title: "Not", Domain: Not(a), Expected: Domain{"!", a[0]}},
+		{Title: "Single", Domain: And(a), Expected: a},
+		{Title: "And skips empty", Domain: And(a, Domain{}), Expected: a},
+		{Title: "Or with empty matches all", Domain: Or(a, Domain{}), Expected: Domain{}},
+		{Title: "Nested", Domain: And(a, Or(b, Not(c))), Expected: Domain{"&", a[0], "|", b[0], "!", c[0]}},
+		{Title: "Implicit and is made explicit", Domain: Or(implicit, a), Expected: Domain{"|", "&", implicit[0], implicit[1], a[0]}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.Title, func(t *testing.T) {
+			if !reflect.DeepEqual(tc.Domain, tc.Expected) {
+				t.Fatalf("expected %v, got %v", tc.Expected, tc.Domain)
+			}
+			if err := tc.Domain.Validate(); err != nil {
+				t.Fatalf("unexpected validation error: %v", err)
+			}
+		})
+	}
+}
+
+func TestDomain_Validate(t *testing.T) {
+	tests := []struct {
+		Title  string
+		Domain Domain
+	}{
+		{Title: "Missing operand", Domain: Domain{"|", []any{"a", "=", 1}}},
+		{Title: "Unknown operator", Domain: Domain{[]any{"a", "~", 1}}},
+		{Title: "Short condition", Domain: Domain{[]any{"a", "="}}},
+		{Title: "Invalid field", Domain: Domain{[]any{2, "=", 1}}},
+		{Title: "In without list", Domain: Domain{[]any{"a", "in", 1}}},
+		{Title: "Invalid term", Domain: Domain{"a"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.Title, func(t *testing.T) {
+			if err := tc.Domain.Validate(); err == nil {
+				t.Fatalf("expected validation error for %v", tc.Domain)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package odoo
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// PlannedCall is a call that a dry-run client would have sent to Odoo.
+type PlannedCall struct {
+	Model  string         `json:"model"`
+	Method string         `json:"method"`
+	Args   []any          `json:"args"`
+	Kwargs map[string]any `json:"kwargs,omitempty"`
+	IDs    []int          `json:"ids,omitempty"` // Placeholder IDs returned by create
+}
+
+// Plan collects, in order, the calls captured by a dry-run client. Records that would
+// have been created get negative placeholder IDs (-1, -2...), so later calls
+// referencing them can be matched in the plan.
+type Plan struct {
+	mu     sync.Mutex
+	calls  []PlannedCall
+	lastID int
+}
+
+func NewPlan() *Plan {
+	return &Plan{}
+}
+
+// Calls returns a copy of the calls captured so far.
+func (p *Plan) Calls() []PlannedCall {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]PlannedCall{}, p.calls...)
+}
+
+func (p *Plan) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.Calls())
+}
+
+// JSON returns the plan indented, one call per object, ready to be printed or diffed.
+func (p *Plan) JSON() (string, error) {
+	planJson, err := json.MarshalIndent(p.Calls(), "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("could not serialize dry-run plan:\n>>> %w", err)
+	}
+	return string(planJson), nil
+}
+
+func (p *Plan) record(model, method string, args []any, kwargs map[string]any) (any, error) {
+	call := PlannedCall{Model: model, Method: method}
+	// Snapshot the arguments as sent, callers may keep modifying their maps
+	argsJson, err := json.Marshal(map[string]any{"args": args, "kwargs": kwargs})
+	if err != nil {
+		return nil, fmt.Errorf("could not serialize dry-run call %v.%v:\n>>> %w", model, method, err)
+	}
+	if err := json.Unmarshal(argsJson, &call); err != nil {
+		return nil, fmt.Errorf("could not serialize dry-run call %v.%v:\n>>> %w", model, method, err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var result any = true
+	if method == "create" {
+		count := 1
+		if len(args) != 0 {
+			if list, ok := args[0].([]map[string]any); ok {
+				count = len(list)
+			} else if list, ok := args[0].([]any); ok {
+				count = len(list)
+			}
+		}
+		ids := make([]any, count)
+		for i := range count {
+			p.lastID--
+			call.IDs = append(call.IDs, p.lastID)
+			ids[i] = float64(p.lastID)
+		}
+		result = ids
+	}
+	p.calls = append(p.calls, call)
+	return result, nil
+}
+
+// WithDryRun returns a client that sends reads to Odoo but captures every other call
+// (create, write, unlink, action_confirm...) into plan instead. Install it with
+// SetDefault to preview what the shopifyodoo functions would change.
+func (c *Client) WithDryRun(plan *Plan) *Client {
+	client := *c
+	client.plan = plan
+	return &client
+}
+
+// IsDryRun reports whether the client captures its writes into a plan, in which case the
+// records it creates cannot be read back.
+func (c *Client) IsDryRun() bool {
+	return c.plan != nil
+}
@@ -0,0 +1,51 @@
+package odoo
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDryRun(t *testing.T) {
+	requests := []rpcRequest{}
+	client := newTestClient(t, &requests, func(rpcRequest) (int, any) {
+		return resultResponse([]any{})
+	})
+	plan := NewPlan()
+	oc := client.WithDryRun(plan).WithCompany(2)
+
+	data := map[string]any{"name": "Test"}
+	id, err := oc.Create("res.partner", data, map[string]any{"xid": "__export__.shopify_customer_1"})
+	if err != nil || id != -1 {
+		t.Fatalf("expected placeholder ID -1, got %v, %v", id, err)
+	}
+	data["name"] = "Changed after create"
+	if err := oc.Write("res.partner", id, map[string]any{"email": "test@example.com"}, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := oc.JsonRpcExecuteKw("sale.order", "action_confirm", []any{[]int{5}}, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Only the ir.model.data lookup of AssignRecordXID reached Odoo
+	if len(requests) != 1 || requests[0].Params["args"].([]any)[4] != "search_read" {
+		t.Fatalf("expected only reads to be sent, got %v", requests)
+	}
+	calls := plan.Calls()
+	methods := []string{}
+	for _, call := range calls {
+		methods = append(methods, call.Model+"."+call.Method)
+	}
+	if strings.Join(methods, ",") != "res.partner.create,ir.model.data.create,res.partner.write,sale.order.action_confirm" {
+		t.Fatalf("unexpected plan: %v", methods)
+	}
+	if calls[0].IDs[0] != -1 || calls[1].IDs[0] != -2 {
+		t.Fatalf("unexpected placeholder IDs: %v, %v", calls[0].IDs, calls[1].IDs)
+	}
+	planJson, err := plan.JSON()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(planJson, "Changed after create") || !strings.Contains(planJson, `"res_id": -1`) || !strings.Contains(planJson, "allowed_company_ids") {
+		t.Fatalf("unexpected plan JSON: %s", planJson)
+	}
+}
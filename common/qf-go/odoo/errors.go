@@ -0,0 +1,84 @@
+package odoo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrNotFound        = errors.New("no matching record found")
+	ErrMultipleResults = errors.New("more than one matching record found")
+)
+
+const (
+	ExceptionValidation   = "odoo.exceptions.ValidationError"
+	ExceptionUser         = "odoo.exceptions.UserError"
+	ExceptionAccess       = "odoo.exceptions.AccessError"
+	ExceptionAccessDenied = "odoo.exceptions.AccessDenied"
+	ExceptionMissing      = "odoo.exceptions.MissingError"
+
+	ExceptionUniqueViolation = "psycopg2.errors.UniqueViolation"
+)
+
+// RPCError is the error object returned by Odoo in a JSON-RPC response.
+type RPCError struct {
+	Code      int
+	Message   string // Generic message, e.g. "Odoo Server Error"
+	Name      string // Exception class, e.g. odoo.exceptions.ValidationError
+	Detail    string // Exception message
+	Debug     string // Server traceback
+	Arguments []any
+}
+
+func (e *RPCError) Error() string {
+	if e.Name == "" {
+		return fmt.Sprintf("error received from Odoo JSON-RPC call: [%d] %s", e.Code, e.Message)
+	}
+	if e.Debug == "" {
+		return fmt.Sprintf("error received from Odoo JSON-RPC call: [%d] %s: %s", e.Code, e.Name, e.Detail)
+	}
+	return fmt.Sprintf("error received from Odoo JSON-RPC call: [%d] %s: %s\n>>> %s", e.Code, e.Name, e.Detail, e.Debug)
+}
+
+// IsException reports whether the exception raised is name, which can be the fully
+// qualified class or just its last part, e.g. "ValidationError".
+func (e *RPCError) IsException(name string) bool {
+	return e.Name == name || strings.HasSuffix(e.Name, "."+name)
+}
+
+func parseRPCError(rError any) *RPCError {
+	rpcError := &RPCError{}
+	errorMap, ok := rError.(map[string]any)
+	if !ok {
+		rpcError.Message = fmt.Sprint(rError)
+		return rpcError
+	}
+	if code, ok := errorMap["code"].(float64); ok {
+		rpcError.Code = int(code)
+	}
+	rpcError.Message, _ = errorMap["message"].(string)
+	if data, ok := errorMap["data"].(map[string]any); ok {
+		rpcError.Name, _ = data["name"].(string)
+		rpcError.Detail, _ = data["message"].(string)
+		rpcError.Debug, _ = data["debug"].(string)
+		rpcError.Arguments, _ = data["arguments"].([]any)
+	}
+	return rpcError
+}
+
+// IsRPCException reports whether err wraps an RPCError for the given exception name.
+func IsRPCException(err error, name string) bool {
+	var rpcError *RPCError
+	return errors.As(err, &rpcError) && rpcError.IsException(name)
+}
+
+// IsUniqueViolation reports whether err is a unique constraint violation raised by
+// PostgreSQL, e.g. on the (module, name) of ir.model.data.
+func IsUniqueViolation(err error) bool {
+	var rpcError *RPCError
+	if !errors.As(err, &rpcError) {
+		return false
+	}
+	return rpcError.IsException("UniqueViolation") || strings.Contains(rpcError.Detail, "duplicate key value violates unique constraint")
+}
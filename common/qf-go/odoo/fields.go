@@ -0,0 +1,207 @@
+package odoo
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Field describes a model field as returned by fields_get.
+type Field struct {
+	Name      string
+	Type      string // char, integer, many2one...
+	String    string // Label
+	Relation  string // Comodel of relational fields
+	Required  bool
+	Readonly  bool
+	Selection []string // Keys of selection fields
+}
+
+// Fields are cached per process for each Odoo database, as they only change when
+// modules are installed or upgraded.
+var fieldsCache = struct {
+	sync.Mutex
+	models map[string]map[string]Field
+}{models: map[string]map[string]Field{}}
+
+func ResetFieldsCache() {
+	fieldsCache.Lock()
+	defer fieldsCache.Unlock()
+	fieldsCache.models = map[string]map[string]Field{}
+}
+
+// FieldsGet returns the fields of model by name, cached for the life of the process.
+func (c *Client) FieldsGet(model string) (map[string]Field, error) {
+	cacheKey := fmt.Sprintf("%s|%s|%s", c.config.URL, c.config.DB, model)
+	fieldsCache.Lock()
+	fields, found := fieldsCache.models[cacheKey]
+	fieldsCache.Unlock()
+	if found {
+		return fields, nil
+	}
+
+	result, err := c.JsonRpcExecuteKw(model, "fields_get", []any{}, map[string]any{
+		"attributes": []string{"type", "string", "relation", "required", "readonly", "selection"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	resultMap, ok := result.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("fields_get result is not valid")
+	}
+
+	fields = make(map[string]Field, len(resultMap))
+	for name, attributes := range resultMap {
+		attributesMap, _ := attributes.(map[string]any)
+		field := Field{Name: name}
+		field.Type, _ = attributesMap["type"].(string)
+		field.String, _ = attributesMap["string"].(string)
+		field.Relation, _ = attributesMap["relation"].(string)
+		field.Required, _ = attributesMap["required"].(bool)
+		field.Readonly, _ = attributesMap["readonly"].(bool)
+		if selection, ok := attributesMap["selection"].([]any); ok {
+			for _, option := range selection {
+				if pair, ok := option.([]any); ok && len(pair) == 2 {
+					if key, ok := pair[0].(string); ok {
+						field.Selection = append(field.Selection, key)
+					}
+				}
+			}
+		}
+		fields[name] = field
+	}
+
+	fieldsCache.Lock()
+	fieldsCache.models[cacheKey] = fields
+	fieldsCache.Unlock()
+	return fields, nil
+}
+
+// FieldValidationError lists the values rejected locally before a create or write.
+type FieldValidationError struct {
+	Model   string
+	Unknown []string          // Fields that do not exist in the model
+	Invalid map[string]string // Field name to reason
+}
+
+func (e *FieldValidationError) Error() string {
+	problems := []string{}
+	if len(e.Unknown) != 0 {
+		problems = append(problems, fmt.Sprintf("unknown fields %v", e.Unknown))
+	}
+	if len(e.Invalid) != 0 {
+		invalid := make([]string, 0, len(e.Invalid))
+		for _, name := range slices.Sorted(maps.Keys(e.Invalid)) {
+			invalid = append(invalid, fmt.Sprintf("%s (%s)", name, e.Invalid[name]))
+		}
+		problems = append(problems, fmt.Sprintf("invalid values for %s", strings.Join(invalid, ", ")))
+	}
+	return fmt.Sprintf("invalid data for Odoo model %s: %s", e.Model, strings.Join(problems, "; "))
+}
+
+// WithFieldValidation returns a client that checks the values of every create and
+// write against the model fields before sending them to Odoo.
+func (c *Client) WithFieldValidation(enabled bool) *Client {
+	client := *c
+	client.config.ValidateFields = enabled
+	return &client
+}
+
+// ValidateValues checks that every key of data is a field of model and that its value
+// has a type Odoo accepts for that field.
+func (c *Client) ValidateValues(model string, data map[string]any) error {
+	fields, err := c.FieldsGet(model)
+	if err != nil {
+		return fmt.Errorf("error getting fields of %v for validation\nERROR=%w", model, err)
+	}
+	validationError := &FieldValidationError{Model: model, Invalid: map[string]string{}}
+	for _, name := range slices.Sorted(maps.Keys(data)) {
+		field, found := fields[name]
+		if !found {
+			validationError.Unknown = append(validationError.Unknown, name)
+			continue
+		}
+		if reason := validateFieldValue(field, data[name]); reason != "" {
+			validationError.Invalid[name] = reason
+		}
+	}
+	if len(validationError.Unknown) != 0 || len(validationError.Invalid) != 0 {
+		return validationError
+	}
+	return nil
+}
+
+var (
+	dateType     = reflect.TypeFor[Date]()
+	datetimeType = reflect.TypeFor[Datetime]()
+)
+
+func validateFieldValue(field Field, value any) string {
+	rValue := reflect.ValueOf(value)
+	for rValue.Kind() == reflect.Pointer {
+		if rValue.IsNil() {
+			return ""
+		}
+		rValue = rValue.Elem()
+	}
+	if !rValue.IsValid() || rValue.Kind() == reflect.Bool && !rValue.Bool() && field.Type != "boolean" {
+		// null and false unset any field type
+		return ""
+	}
+	expected := ""
+	valid := true
+	switch field.Type {
+	case "char", "text", "html", "binary", "reference":
+		expected = "string"
+		valid = rValue.Kind() == reflect.String
+	case "selection":
+		expected = "string"
+		valid = rValue.Kind() == reflect.String
+		if valid && len(field.Selection) != 0 && !slices.Contains(field.Selection, rValue.String()) {
+			return fmt.Sprintf("expected one of %v, got %q", field.Selection, rValue.String())
+		}
+	case "boolean":
+		expected = "boolean"
+		valid = rValue.Kind() == reflect.Bool
+	case "integer", "many2one_reference":
+		expected = "integer"
+		valid = isInteger(rValue)
+	case "many2one":
+		// Many2One, as decoded from a read, marshals to its ID
+		expected = "integer or odoo.Many2One"
+		valid = isInteger(rValue) || rValue.Type() == many2OneType
+	case "float", "monetary":
+		expected = "number"
+		valid = isInteger(rValue) || rValue.Kind() == reflect.Float32 || rValue.Kind() == reflect.Float64
+	case "one2many", "many2many":
+		expected = "list of commands or IDs"
+		valid = rValue.Kind() == reflect.Slice || rValue.Kind() == reflect.Array
+	case "date":
+		// time.Time marshals to RFC 3339, which Odoo rejects
+		expected = "date string or odoo.Date"
+		valid = rValue.Kind() == reflect.String || rValue.Type() == dateType
+	case "datetime":
+		expected = "datetime string or odoo.Datetime"
+		valid = rValue.Kind() == reflect.String || rValue.Type() == datetimeType
+	}
+	if !valid {
+		return fmt.Sprintf("expected %s for %s field, got %T", expected, field.Type, value)
+	}
+	return ""
+}
+
+func isInteger(rValue reflect.Value) bool {
+	switch rValue.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	case reflect.Float32, reflect.Float64:
+		return rValue.Float() == math.Trunc(rValue.Float())
+	}
+	return false
+}
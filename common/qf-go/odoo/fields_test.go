@@ -0,0 +1,142 @@
+package odoo
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func partnerFieldsResponse(req rpcRequest) (int, any) {
+	args := req.Params["args"].([]any)
+	switch args[4] {
+	case "fields_get":
+		return resultResponse(map[string]any{
+			"name":                           map[string]any{"type": "char", "string": "Name"},
+			"customer_delivery_instructions": map[string]any{"type": "text", "string": "Delivery Instructions"},
+			"qf_pricelist_id":                map[string]any{"type": "many2one", "relation": "product.pricelist"},
+			"contact_role_code_ids":          map[string]any{"type": "many2many", "relation": "contact.role.code"},
+			"customer_rank":                  map[string]any{"type": "integer"},
+			"date":                           map[string]any{"type": "date"},
+			"signup_expiration":              map[string]any{"type": "datetime"},
+			"type":                           map[string]any{"type": "selection", "selection": []any{[]any{"contact", "Contact"}, []any{"delivery", "Delivery"}}},
+		})
+	case "create":
+		return resultResponse([]any{10.0})
+	}
+	return resultResponse(true)
+}
+
+func TestFieldsGet_Cached(t *testing.T) {
+	ResetFieldsCache()
+	defer ResetFieldsCache()
+	requests := []rpcRequest{}
+	client := newTestClient(t, &requests, partnerFieldsResponse)
+
+	fields, err := client.FieldsGet("res.partner")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fields["qf_pricelist_id"].Relation != "product.pricelist" || len(fields["type"].Selection) != 2 {
+		t.Fatalf("unexpected fields: %+v", fields)
+	}
+	if _, err := client.WithCompany(2).FieldsGet("res.partner"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(requests) != 1 {
+		t.Fatalf("expected fields to be fetched once, got %d requests", len(requests))
+	}
+}
+
+func TestValidateValues(t *testing.T) {
+	ResetFieldsCache()
+	defer ResetFieldsCache()
+	client := newTestClient(t, nil, partnerFieldsResponse)
+
+	err := client.ValidateValues("res.partner", map[string]any{
+		"name":                           "Test",
+		"customer_delivery_instructions": false,
+		"qf_pricelist_id":                "5",
+		"contact_role_code_ids":          []any{[]any{6, 0, []int{1}}},
+		"customer_rank":                  1.0,
+		"type":                           "billing",
+		"no_handling_fee_reason":         "x",
+	})
+	validationError := &FieldValidationError{}
+	if !errors.As(err, &validationError) {
+		t.Fatalf("expected FieldValidationError, got %v", err)
+	}
+	if len(validationError.Unknown) != 1 || validationError.Unknown[0] != "no_handling_fee_reason" {
+		t.Fatalf("unexpected unknown fields: %v", validationError.Unknown)
+	}
+	if len(validationError.Invalid) != 2 || validationError.Invalid["qf_pricelist_id"] == "" || validationError.Invalid["type"] == "" {
+		t.Fatalf("unexpected invalid fields: %v", validationError.Invalid)
+	}
+	if !strings.Contains(err.Error(), "no_handling_fee_reason") || !strings.Contains(err.Error(), "qf_pricelist_id") {
+		t.Fatalf("expected error to list the rejected keys, got %v", err)
+	}
+}
+
+func TestValidateValues_Dates(t *testing.T) {
+	ResetFieldsCache()
+	defer ResetFieldsCache()
+	client := newTestClient(t, nil, partnerFieldsResponse)
+	now := time.Now()
+
+	valid := []map[string]any{
+		{"date": "2025-06-10", "signup_expiration": "2025-06-10 14:00:00"},
+		{"date": NewDate(now), "signup_expiration": NewDatetime(now)},
+		{"date": false, "signup_expiration": false},
+	}
+	for _, values := range valid {
+		if err := client.ValidateValues("res.partner", values); err != nil {
+			t.Fatalf("unexpected error for %v: %v", values, err)
+		}
+	}
+	err := client.ValidateValues("res.partner", map[string]any{"date": now, "signup_expiration": now})
+	validationError := &FieldValidationError{}
+	if !errors.As(err, &validationError) || len(validationError.Invalid) != 2 {
+		t.Fatalf("expected time.Time to be rejected, got %v", err)
+	}
+}
+
+func TestValidateValues_Many2One(t *testing.T) {
+	ResetFieldsCache()
+	defer ResetFieldsCache()
+	client := newTestClient(t, nil, partnerFieldsResponse)
+
+	valid := []map[string]any{
+		{"qf_pricelist_id": 3},
+		{"qf_pricelist_id": Many2One{ID: 3, Name: "Wholesale"}},
+		{"qf_pricelist_id": &Many2One{ID: 3, Name: "Wholesale"}},
+		{"qf_pricelist_id": Many2One{}},
+	}
+	for _, values := range valid {
+		if err := client.ValidateValues("res.partner", values); err != nil {
+			t.Fatalf("unexpected error for %v: %v", values, err)
+		}
+	}
+	err := client.ValidateValues("res.partner", map[string]any{"qf_pricelist_id": "Wholesale"})
+	validationError := &FieldValidationError{}
+	if !errors.As(err, &validationError) || len(validationError.Invalid) != 1 {
+		t.Fatalf("expected a name to be rejected, got %v", err)
+	}
+}
+
+func TestCreate_FieldValidation(t *testing.T) {
+	ResetFieldsCache()
+	defer ResetFieldsCache()
+	requests := []rpcRequest{}
+	client := newTestClient(t, &requests, partnerFieldsResponse).WithFieldValidation(true)
+
+	if _, err := client.Create("res.partner", map[string]any{"name": "Test", "no_handling_fee_reason": "x"}, nil); err == nil {
+		t.Fatalf("expected validation error")
+	}
+	if len(requests) != 1 {
+		t.Fatalf("expected only fields_get to be called, got %d requests", len(requests))
+	}
+	id, err := client.Create("res.partner", map[string]any{"name": "Test", "qf_pricelist_id": 3}, nil)
+	if err != nil || id != 10 {
+		t.Fatalf("unexpected create result %v, %v", id, err)
+	}
+}
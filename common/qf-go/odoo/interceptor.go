@@ -0,0 +1,127 @@
+package odoo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"sync"
+	"time"
+)
+
+// CallInfo describes an execute_kw call to the interceptors. Duration and Err are set
+// once the call returns, including its retries.
+type CallInfo struct {
+	Model    string
+	Method   string
+	ArgsSize int // Size of the JSON encoded args and kwargs, in bytes
+	Duration time.Duration
+	Err      error
+}
+
+// Interceptor wraps the execute_kw calls sent to Odoo. It must call next to send the
+// call, and can then inspect info or change the result. Calls answered by a dry-run
+// plan or a cache are not sent, so they are not intercepted.
+type Interceptor func(info *CallInfo, next func() (any, error)) (any, error)
+
+// WithInterceptors returns a client sending its calls through the interceptors, after
+// those already set. The first interceptor is the outermost.
+func (c *Client) WithInterceptors(interceptors ...Interceptor) *Client {
+	client := *c
+	client.interceptors = append(append([]Interceptor{}, c.interceptors...), interceptors...)
+	return &client
+}
+
+func (c *Client) intercept(model, method string, args []any, kwargs map[string]any, call func() (any, error)) (any, error) {
+	info := &CallInfo{Model: model, Method: method}
+	if argsJson, err := json.Marshal([]any{args, kwargs}); err == nil {
+		info.ArgsSize = len(argsJson)
+	}
+	next := func() (any, error) {
+		start := time.Now()
+		result, err := call()
+		info.Duration = time.Since(start)
+		info.Err = err
+		return result, err
+	}
+	for i := len(c.interceptors) - 1; i >= 0; i-- {
+		interceptor, inner := c.interceptors[i], next
+		next = func() (any, error) {
+			return interceptor(info, inner)
+		}
+	}
+	return next()
+}
+
+// LogInterceptor logs every call with its model, method, args size and duration, at
+// debug level, or at error level when it fails. A nil logger is slog.Default().
+func LogInterceptor(logger *slog.Logger) Interceptor {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return func(info *CallInfo, next func() (any, error)) (any, error) {
+		result, err := next()
+		level, message := slog.LevelDebug, "odoo call"
+		attrs := []slog.Attr{
+			slog.String("model", info.Model),
+			slog.String("method", info.Method),
+			slog.Int("args_size", info.ArgsSize),
+			slog.Duration("duration", info.Duration),
+		}
+		if err != nil {
+			level, message = slog.LevelError, "odoo call failed"
+			attrs = append(attrs, slog.String("error", err.Error()))
+		}
+		logger.LogAttrs(context.Background(), level, message, attrs...)
+		return result, err
+	}
+}
+
+// CallCounter counts the calls sent through its Intercept method, e.g. per sync. The
+// zero value is ready to use.
+type CallCounter struct {
+	mu       sync.Mutex
+	calls    int
+	errors   int
+	duration time.Duration
+	byMethod map[string]int
+}
+
+func (c *CallCounter) Intercept(info *CallInfo, next func() (any, error)) (any, error) {
+	result, err := next()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls++
+	if err != nil {
+		c.errors++
+	}
+	c.duration += info.Duration
+	if c.byMethod == nil {
+		c.byMethod = map[string]int{}
+	}
+	c.byMethod[info.Model+"."+info.Method]++
+	return result, err
+}
+
+// Calls returns the number of calls, of failed calls, and the time spent in them.
+func (c *CallCounter) Calls() (calls int, errors int, duration time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls, c.errors, c.duration
+}
+
+// ByMethod returns the number of calls per "model.method".
+func (c *CallCounter) ByMethod() map[string]int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return maps.Clone(c.byMethod)
+}
+
+func (c *CallCounter) String() string {
+	calls, errors, duration := c.Calls()
+	if errors != 0 {
+		return fmt.Sprintf("%d calls (%d failed) / %.1fs", calls, errors, duration.Seconds())
+	}
+	return fmt.Sprintf("%d calls / %.1fs", calls, duration.Seconds())
+}
@@ -0,0 +1,61 @@
+package odoo
+
+import (
+	"bytes"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestInterceptors(t *testing.T) {
+	client := newTestClient(t, nil, func(request rpcRequest) (int, any) {
+		if request.Params["args"].([]any)[4] == "unlink" {
+			return 200, map[string]any{"jsonrpc": "2.0", "id": 1, "error": map[string]any{
+				"code": 200.0, "message": "Odoo Server Error", "data": map[string]any{"name": ExceptionMissing, "message": "Missing"},
+			}}
+		}
+		return resultResponse(3.0)
+	})
+	order := []string{}
+	trace := func(name string) Interceptor {
+		return func(info *CallInfo, next func() (any, error)) (any, error) {
+			order = append(order, name+" before")
+			result, err := next()
+			order = append(order, name+" after")
+			return result, err
+		}
+	}
+	counter := &CallCounter{}
+	logs := &bytes.Buffer{}
+	logger := slog.New(slog.NewTextHandler(logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	intercepted := client.WithInterceptors(trace("outer"), trace("inner")).WithInterceptors(counter.Intercept, LogInterceptor(logger))
+
+	if count, err := intercepted.SearchCount("res.partner", []any{}, nil); err != nil || count != 3 {
+		t.Fatalf("unexpected result %v, %v", count, err)
+	}
+	if !reflect.DeepEqual(order, []string{"outer before", "inner before", "inner after", "outer after"}) {
+		t.Fatalf("unexpected interceptor order: %v", order)
+	}
+	if err := intercepted.Unlink("res.partner", 1, nil); err == nil {
+		t.Fatalf("expected error")
+	}
+	if _, err := client.SearchCount("res.partner", []any{}, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if calls, errors, _ := counter.Calls(); calls != 2 || errors != 1 {
+		t.Fatalf("expected 2 calls with 1 error, got %v, %v", calls, errors)
+	}
+	if byMethod := counter.ByMethod(); byMethod["res.partner.search_count"] != 1 || byMethod["res.partner.unlink"] != 1 {
+		t.Fatalf("unexpected calls by method: %v", byMethod)
+	}
+	if !strings.HasPrefix(counter.String(), "2 calls (1 failed) / ") {
+		t.Fatalf("unexpected summary: %v", counter)
+	}
+	lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], "level=DEBUG msg=\"odoo call\" model=res.partner method=search_count args_size=") ||
+		!strings.Contains(lines[1], "level=ERROR msg=\"odoo call failed\" model=res.partner method=unlink") {
+		t.Fatalf("unexpected logs:\n%s", logs)
+	}
+}
@@ -0,0 +1,113 @@
+package odoo
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+	"strings"
+)
+
+var DefaultPageSize = 200
+
+type IterOptions struct {
+	PageSize int    // Defaults to DefaultPageSize
+	Order    string // Defaults to "id", which pages by id instead of offset
+	Context  map[string]any
+}
+
+// SearchReadIter returns an iterator over all records matching domain, fetched one
+// page at a time so only a page is held in memory. Iteration stops at the first error,
+// which is yielded with a nil record.
+//
+// Without Order, pages are requested by increasing id (id > last id seen), which is
+// stable while records are being created. With Order, pages are requested by offset,
+// with id added as a tie-breaker so no record is skipped or repeated between pages.
+func (c *Client) SearchReadIter(model string, domain []any, fields []string, options IterOptions) iter.Seq2[map[string]any, error] {
+	pageSize := options.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	keyset := options.Order == ""
+	order := options.Order
+	if keyset {
+		order = "id"
+		if len(fields) != 0 && !slices.Contains(fields, "id") {
+			fields = append(slices.Clone(fields), "id")
+		}
+	} else if !orderHasId(order) {
+		order += ", id"
+	}
+
+	return func(yield func(map[string]any, error) bool) {
+		lastId := 0
+		offset := 0
+		for {
+			pageDomain := domain
+			pageOptions := SearchOptions{Limit: pageSize, Order: order, Context: options.Context}
+			if keyset {
+				if lastId != 0 {
+					pageDomain = And(Domain(domain), Cond("id", OpGt, lastId))
+				}
+			} else {
+				pageOptions.Offset = offset
+			}
+			records, err := c.SearchReadWithOptions(model, pageDomain, fields, pageOptions)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			for _, record := range records {
+				if !yield(record, nil) {
+					return
+				}
+			}
+			if len(records) < pageSize {
+				return
+			}
+			offset += len(records)
+			id, ok := records[len(records)-1]["id"].(float64)
+			if keyset && !ok {
+				yield(nil, fmt.Errorf("cannot page through %v without record ids", model))
+				return
+			}
+			lastId = int(id)
+		}
+	}
+}
+
+func orderHasId(order string) bool {
+	for _, part := range strings.Split(order, ",") {
+		fields := strings.Fields(part)
+		if len(fields) != 0 && fields[0] == "id" {
+			return true
+		}
+	}
+	return false
+}
+
+// SearchReadIntoIter is SearchReadIter decoding each record into T, reading only the
+// fields declared in T's `odoo:"..."` tags. A nil client uses Default.
+func SearchReadIntoIter[T any](c *Client, model string, domain []any, options IterOptions) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var empty T
+		c, err := orDefault(c)
+		if err != nil {
+			yield(empty, err)
+			return
+		}
+		for record, err := range c.SearchReadIter(model, domain, FieldsOf[T](), options) {
+			if err != nil {
+				yield(empty, err)
+				return
+			}
+			decoded, err := DecodeRecord[T](record)
+			if err != nil {
+				yield(empty, err)
+				return
+			}
+			if !yield(decoded, nil) {
+				return
+			}
+		}
+	}
+}
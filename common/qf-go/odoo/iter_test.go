@@ -0,0 +1,81 @@
+package odoo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSearchReadIter_Keyset(t *testing.T) {
+	requests := []rpcRequest{}
+	client := newTestClient(t, &requests, func(request rpcRequest) (int, any) {
+		page := map[int][]any{
+			1: {map[string]any{"id": 1.0}, map[string]any{"id": 2.0}},
+			2: {map[string]any{"id": 5.0}, map[string]any{"id": 6.0}},
+			3: {map[string]any{"id": 9.0}},
+		}[len(requests)]
+		return resultResponse(page)
+	})
+
+	ids := []int{}
+	for record, err := range client.SearchReadIter("res.partner", []any{[]any{"active", "=", true}}, []string{"name"}, IterOptions{PageSize: 2}) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ids = append(ids, int(record["id"].(float64)))
+	}
+	if !reflect.DeepEqual(ids, []int{1, 2, 5, 6, 9}) {
+		t.Fatalf("unexpected ids: %v", ids)
+	}
+	if len(requests) != 3 {
+		t.Fatalf("expected 3 pages, got %d", len(requests))
+	}
+	kwargs := requests[2].Params["args"].([]any)[6].(map[string]any)
+	expectedDomain := []any{"&", []any{"active", "=", true}, []any{"id", ">", 6.0}}
+	if !reflect.DeepEqual(kwargs["domain"], expectedDomain) || kwargs["order"] != "id" || kwargs["offset"] != nil {
+		t.Fatalf("unexpected page request: %v", kwargs)
+	}
+	if !reflect.DeepEqual(kwargs["fields"], []any{"name", "id"}) {
+		t.Fatalf("expected id to be requested, got %v", kwargs["fields"])
+	}
+}
+
+func TestSearchReadIter_Offset(t *testing.T) {
+	requests := []rpcRequest{}
+	client := newTestClient(t, &requests, func(request rpcRequest) (int, any) {
+		if len(requests) == 1 {
+			return resultResponse([]any{map[string]any{"id": 9.0}, map[string]any{"id": 3.0}})
+		}
+		return resultResponse([]any{})
+	})
+
+	count := 0
+	for _, err := range client.SearchReadIter("sale.order", []any{}, nil, IterOptions{PageSize: 2, Order: "date_order desc"}) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		count++
+	}
+	if count != 2 || len(requests) != 2 {
+		t.Fatalf("expected 2 records in 2 requests, got %d in %d", count, len(requests))
+	}
+	kwargs := requests[1].Params["args"].([]any)[6].(map[string]any)
+	if kwargs["order"] != "date_order desc, id" || kwargs["offset"] != 2.0 {
+		t.Fatalf("unexpected page request: %v", kwargs)
+	}
+}
+
+func TestSearchReadIntoIter_Break(t *testing.T) {
+	requests := []rpcRequest{}
+	client := newTestClient(t, &requests, func(request rpcRequest) (int, any) {
+		return resultResponse([]any{map[string]any{"id": 1.0, "name": "A"}, map[string]any{"id": 2.0, "name": "B"}})
+	})
+	for record, err := range SearchReadIntoIter[decodeTestRecord](client, "res.partner", []any{}, IterOptions{PageSize: 2}) {
+		if err != nil || record.Name != "A" {
+			t.Fatalf("unexpected record: %+v, %v", record, err)
+		}
+		break
+	}
+	if len(requests) != 1 {
+		t.Fatalf("expected iteration to stop after break, got %d requests", len(requests))
+	}
+}
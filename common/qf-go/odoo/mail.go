@@ -0,0 +1,70 @@
+package odoo
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+// Message is a message posted in the chatter of a record.
+type Message struct {
+	Body          string // HTML
+	Subject       string
+	Comment       bool // Post a comment sent to the followers instead of an internal note
+	AttachmentIds []int
+}
+
+// MessagePost posts a message in the chatter of a record, returning the mail.message ID.
+func (c *Client) MessagePost(model string, id int, message Message) (int, error) {
+	kwargs := map[string]any{
+		"body":          message.Body,
+		"message_type":  "comment",
+		"subtype_xmlid": "mail.mt_note",
+	}
+	if message.Comment {
+		kwargs["subtype_xmlid"] = "mail.mt_comment"
+	}
+	if message.Subject != "" {
+		kwargs["subject"] = message.Subject
+	}
+	if len(message.AttachmentIds) != 0 {
+		kwargs["attachment_ids"] = message.AttachmentIds
+	}
+	// Since 18.0 the body is escaped unless flagged as HTML
+	if version, err := c.ServerVersion(); err == nil && version.Major >= 18 {
+		kwargs["body_is_html"] = true
+	}
+	result, err := c.JsonRpcExecuteKw(model, "message_post", []any{[]int{id}}, kwargs)
+	if err != nil {
+		return 0, fmt.Errorf("error posting message on %v(%v):\n>>> %w", model, id, err)
+	}
+	switch result := result.(type) {
+	case float64:
+		return int(result), nil
+	case []any:
+		if len(result) == 1 {
+			if messageId, ok := result[0].(float64); ok {
+				return int(messageId), nil
+			}
+		}
+	case bool:
+		// Dry run
+		return 0, nil
+	}
+	return 0, fmt.Errorf("invalid result from message_post, expected message ID, got %v", result)
+}
+
+// Attach attaches a file to a record, returning the ir.attachment ID.
+func (c *Client) Attach(model string, id int, name string, content []byte, mimetype string) (int, error) {
+	attachmentId, err := c.Create("ir.attachment", map[string]any{
+		"name":      name,
+		"type":      "binary",
+		"datas":     base64.StdEncoding.EncodeToString(content),
+		"mimetype":  mimetype,
+		"res_model": model,
+		"res_id":    id,
+	}, nil)
+	if err != nil {
+		return 0, fmt.Errorf("error attaching %v to %v(%v):\n>>> %w", name, model, id, err)
+	}
+	return attachmentId, nil
+}
@@ -0,0 +1,48 @@
+package odoo
+
+import (
+	"encoding/base64"
+	"reflect"
+	"testing"
+)
+
+func TestMessagePostAndAttach(t *testing.T) {
+	requests := []rpcRequest{}
+	client := newTestClient(t, &requests, func(request rpcRequest) (int, any) {
+		switch request.Params["args"].([]any)[4] {
+		case "create":
+			return resultResponse([]any{12.0})
+		case "message_post":
+			return resultResponse(34.0)
+		}
+		return resultResponse(true)
+	})
+	client.config.Version = "18.0"
+
+	attachmentId, err := client.Attach("sale.order", 5, "payload.json", []byte(`{"id": 1}`), "application/json")
+	if err != nil || attachmentId != 12 {
+		t.Fatalf("expected attachment 12, got %v, %v", attachmentId, err)
+	}
+	values := requests[0].Params["args"].([]any)[5].([]any)[0].([]any)[0].(map[string]any)
+	if values["res_model"] != "sale.order" || values["res_id"] != 5.0 || values["datas"] != base64.StdEncoding.EncodeToString([]byte(`{"id": 1}`)) {
+		t.Fatalf("unexpected attachment values: %v", values)
+	}
+
+	messageId, err := client.MessagePost("sale.order", 5, Message{Body: "<p>Synced</p>", AttachmentIds: []int{attachmentId}})
+	if err != nil || messageId != 34 {
+		t.Fatalf("expected message 34, got %v, %v", messageId, err)
+	}
+	args := requests[1].Params["args"].([]any)
+	kwargs := args[6].(map[string]any)
+	expected := map[string]any{
+		"body":           "<p>Synced</p>",
+		"message_type":   "comment",
+		"subtype_xmlid":  "mail.mt_note",
+		"attachment_ids": []any{12.0},
+		"body_is_html":   true,
+		"context":        map[string]any{},
+	}
+	if args[4] != "message_post" || !reflect.DeepEqual(args[5], []any{[]any{5.0}}) || !reflect.DeepEqual(kwargs, expected) {
+		t.Fatalf("unexpected message_post call: %v", args)
+	}
+}
@@ -1,16 +1,12 @@
 package odoo
 
 import (
-	"bytes"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"maps"
-	"net/http"
-	"os"
-	"reflect"
+	"qf/go/helpers"
+	"slices"
 	"strings"
-	"time"
 )
 
 var CompanyQF = 2
@@ -45,106 +41,63 @@ func (c *command) Set(ids []int) []any {
 
 var Command command
 
-func jsonRpc(service string, method string, args []any) (any, error) {
-	db := os.Getenv("ODOO_DB")
-	uid := os.Getenv("ODOO_USER_ID")
-	pwd := os.Getenv("ODOO_PASSWORD")
-	domain := os.Getenv("ODOO_DOMAIN")
-	if !(db != "" && domain != "" && uid != "" && pwd != "") {
-		return nil, fmt.Errorf("invalid or incomplete Odoo environment variables")
-	}
-
-	url := fmt.Sprintf("https://%s/jsonrpc", domain)
-	body := map[string]any{
-		"jsonrpc": "2.0",
-		"method":  "call",
-		"id":      uid,
-		"params": map[string]any{
-			"service": service,
-			"method":  method,
-			"args":    append([]any{db, uid, pwd}, args...),
-		},
-	}
+func (c *Client) JsonRpcExecuteKw(model, method string, args []any, kwargs map[string]any) (any, error) {
+	callKwargs := map[string]any{}
+	maps.Copy(callKwargs, kwargs)
+	callKwargs["context"] = c.callContext(kwargs["context"])
+	return c.executeKw(model, method, args, callKwargs)
+}
 
-	bodyJson, err := json.Marshal(body)
-	if err != nil {
-		return nil, fmt.Errorf("could not serialize json for Odoo JSON-RPC call:\n>>> %w", err)
-	}
+func (c *Client) SearchRead(model string, domain []any, fields []string, limit int, context map[string]any) ([]map[string]any, error) {
+	return c.SearchReadWithOptions(model, domain, fields, SearchOptions{Limit: limit, Context: context})
+}
 
-	client := &http.Client{Timeout: 30 * time.Second}
-	response, err := client.Post(url, "application/json", bytes.NewBuffer(bodyJson))
-	if err != nil {
-		return nil, fmt.Errorf("request error during Odoo JSON-RPC call:\n>>> %w", err)
-	}
-	defer response.Body.Close()
+type SearchOptions struct {
+	Offset  int
+	Limit   int
+	Order   string // e.g. "date_order desc, id"
+	Context map[string]any
+}
 
-	rBody, err := io.ReadAll(response.Body)
-	if err != nil {
-		return nil, fmt.Errorf("error reading response from Odoo JSON-RPC call:\n>>> %w", err)
+func (c *Client) SearchReadWithOptions(model string, domain []any, fields []string, options SearchOptions) ([]map[string]any, error) {
+	kwargs := map[string]any{
+		"domain":  domain,
+		"fields":  fields,
+		"limit":   options.Limit,
+		"context": options.Context,
 	}
-
-	if response.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("non-200 response from Odoo JSON-RPC call: [%s] %s", response.Status, string(rBody))
+	if options.Offset != 0 {
+		kwargs["offset"] = options.Offset
 	}
-
-	rJson := map[string]any{}
-	err = json.Unmarshal(rBody, &rJson)
-	if err != nil {
-		return nil, fmt.Errorf("invalid json response from Odoo JSON-RPC call: %s\n>>> %w", string(rBody), err)
+	if options.Order != "" {
+		kwargs["order"] = options.Order
 	}
-
-	rError, rErrorExist := rJson["error"]
-	if rErrorExist {
-		errMsg := "error received from Odoo JSON-RPC call: %s"
-		rErrorJson, err := json.MarshalIndent(rError, "", "  ")
-		if err != nil {
-			return nil, fmt.Errorf(errMsg, rError)
-		}
-		return nil, fmt.Errorf(errMsg, rErrorJson)
+	records, err := c.JsonRpcExecuteKw(model, "search_read", []any{}, kwargs)
+	if err != nil {
+		return nil, err
 	}
 
-	result, resultOk := rJson["result"]
-	if !resultOk {
-		return nil, fmt.Errorf("result not found in response from Odoo JSON-RPC call: %s", string(rBody))
+	recordsListAny, ok := records.([]any)
+	if !ok {
+		return nil, fmt.Errorf("search_read result is not valid")
 	}
 
-	return result, nil
-}
-
-var globalContext = &map[string]any{}
-
-func GlobalContext(context map[string]any) (reset func()) {
-	currentContext := globalContext
-	maps.Copy(context, *globalContext)
-	globalContext = &context
-	return func() {
-		globalContext = currentContext
+	recordsListMap := make([]map[string]any, len(recordsListAny))
+	for i, record := range recordsListAny {
+		recordMap, ok := record.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("search_read result values are not valid")
+		}
+		recordsListMap[i] = recordMap
 	}
-}
 
-func JsonRpcExecuteKw(model, method string, args []any, kwargs map[string]any) (any, error) {
-	if kwargs == nil {
-		kwargs = map[string]any{}
-	}
-	ctx := *globalContext
-	kwargsContext, kwargsContextFound := kwargs["context"]
-	if kwargsContextFound && kwargsContext != nil && reflect.ValueOf(kwargsContext).Kind() == reflect.Map {
-		maps.Copy(ctx, kwargsContext.(map[string]any))
-	}
-	kwargs["context"] = ctx
-	return jsonRpc("object", "execute_kw", []any{model, method, args, kwargs})
+	return recordsListMap, nil
 }
 
-func SearchRead(model string, domain []any, fields []string, limit int, context map[string]any) ([]map[string]any, error) {
-	ctx := *globalContext
-	if context != nil {
-		maps.Copy(ctx, context)
-	}
-	records, err := JsonRpcExecuteKw(model, "search_read", []any{}, map[string]any{
-		"domain":  domain,
+func (c *Client) Read(model string, ids []int, fields []string, context map[string]any) ([]map[string]any, error) {
+	records, err := c.JsonRpcExecuteKw(model, "read", []any{ids}, map[string]any{
 		"fields":  fields,
-		"limit":   limit,
-		"context": ctx,
+		"context": context,
 	})
 	if err != nil {
 		return nil, err
@@ -152,14 +105,14 @@ func SearchRead(model string, domain []any, fields []string, limit int, context
 
 	recordsListAny, ok := records.([]any)
 	if !ok {
-		return nil, fmt.Errorf("search_read result is not valid")
+		return nil, fmt.Errorf("read result is not valid")
 	}
 
 	recordsListMap := make([]map[string]any, len(recordsListAny))
 	for i, record := range recordsListAny {
 		recordMap, ok := record.(map[string]any)
 		if !ok {
-			return nil, fmt.Errorf("search_read result values are not valid")
+			return nil, fmt.Errorf("read result values are not valid")
 		}
 		recordsListMap[i] = recordMap
 	}
@@ -167,8 +120,8 @@ func SearchRead(model string, domain []any, fields []string, limit int, context
 	return recordsListMap, nil
 }
 
-func SearchCount(model string, domain []any, context map[string]any) (int, error) {
-	count, err := JsonRpcExecuteKw(model, "search_count", []any{
+func (c *Client) SearchCount(model string, domain []any, context map[string]any) (int, error) {
+	count, err := c.JsonRpcExecuteKw(model, "search_count", []any{
 		domain,
 	}, map[string]any{
 		"context": context,
@@ -185,26 +138,29 @@ func SearchCount(model string, domain []any, context map[string]any) (int, error
 	return int(countFloat), nil
 }
 
-func SearchReadOne(model string, domain []any, fields []string, context map[string]any) (map[string]any, error) {
-	count, err := SearchCount(model, domain, context)
+func (c *Client) SearchReadOne(model string, domain []any, fields []string, context map[string]any) (map[string]any, error) {
+	count, err := c.SearchCount(model, domain, context)
 	if err != nil {
 		return nil, err
 	}
-	if count != 1 {
-		return nil, fmt.Errorf("search expected exactly 1 result, %d received", count)
+	if count == 0 {
+		return nil, fmt.Errorf("search expected exactly 1 result, 0 received: %w", ErrNotFound)
 	}
-	records, err := SearchRead(model, domain, fields, 1, context)
+	if count > 1 {
+		return nil, fmt.Errorf("search expected exactly 1 result, %d received: %w", count, ErrMultipleResults)
+	}
+	records, err := c.SearchRead(model, domain, fields, 1, context)
 	if err != nil {
 		return nil, err
 	}
 	return records[0], nil
 }
 
-func SearchReadById(model string, id int, fields []string, context map[string]any) (map[string]any, error) {
-	return SearchReadOne(model, []any{[]any{"id", "=", id}}, fields, nil)
+func (c *Client) SearchReadById(model string, id int, fields []string, context map[string]any) (map[string]any, error) {
+	return c.SearchReadOne(model, []any{[]any{"id", "=", id}}, fields, nil)
 }
 
-func ReadRecordByXID(model string, xid string, fields []string) (map[string]any, error) {
+func (c *Client) ReadRecordByXID(model string, xid string, fields []string) (map[string]any, error) {
 	splitXid := strings.Split(xid, ".")
 	if len(splitXid) != 2 {
 		return nil, fmt.Errorf("invalid xid: %s", xid)
@@ -212,7 +168,7 @@ func ReadRecordByXID(model string, xid string, fields []string) (map[string]any,
 	xidModule := splitXid[0]
 	xidName := splitXid[1]
 
-	modelData, err := SearchRead(
+	modelData, err := c.SearchRead(
 		"ir.model.data",
 		[]any{
 			[]any{"module", "=", xidModule},
@@ -226,7 +182,7 @@ func ReadRecordByXID(model string, xid string, fields []string) (map[string]any,
 	}
 
 	if len(modelData) > 1 {
-		return nil, fmt.Errorf("non-unique results for XID %s", xid)
+		return nil, fmt.Errorf("non-unique results for XID %s: %w", xid, ErrMultipleResults)
 	}
 
 	if len(modelData) == 0 {
@@ -240,7 +196,7 @@ func ReadRecordByXID(model string, xid string, fields []string) (map[string]any,
 	}
 
 	recordId := int(modelData[0]["res_id"].(float64))
-	recordData, err := SearchReadOne(
+	recordData, err := c.SearchReadOne(
 		model,
 		[]any{
 			[]any{"id", "=", recordId},
@@ -249,10 +205,10 @@ func ReadRecordByXID(model string, xid string, fields []string) (map[string]any,
 		map[string]any{"active_test": false},
 	)
 	if err != nil {
-		if strings.Contains(err.Error(), " 0 received") {
+		if errors.Is(err, ErrNotFound) {
 			// The XID (ir.model.data) remains in Odoo, but the related record was deleted
 			// So we can safely delete the XID and return that no records were found
-			Unlink("ir.model.data", int(modelData[0]["id"].(float64)), nil)
+			c.Unlink("ir.model.data", int(modelData[0]["id"].(float64)), nil)
 			return nil, nil
 		}
 		return nil, fmt.Errorf("error reading data for record %v of type %v with id %v\nERROR=%w", xid, model, recordId, err)
@@ -261,15 +217,77 @@ func ReadRecordByXID(model string, xid string, fields []string) (map[string]any,
 	return recordData, nil
 }
 
-func GetIDByXID(model string, xid string) (int, error) {
-	rec, err := ReadRecordByXID(model, xid, []string{"id"})
+func (c *Client) GetIDByXID(model string, xid string) (int, error) {
+	rec, err := c.ReadRecordByXID(model, xid, []string{"id"})
 	if err != nil || rec == nil {
 		return 0, err
 	}
 	return int(rec["id"].(float64)), nil
 }
 
-func AssignRecordXID(model string, id int, xid string) error {
+// GetIDsByXIDs resolves many XIDs of the same model at once, with one ir.model.data
+// query and one existence check. XIDs not found are left out of the result, and
+// XIDs pointing to deleted records are removed as ReadRecordByXID does.
+func (c *Client) GetIDsByXIDs(model string, xids []string) (map[string]int, error) {
+	idsByXid := map[string]int{}
+	if len(xids) == 0 {
+		return idsByXid, nil
+	}
+
+	namesByModule := map[string][]string{}
+	for _, xid := range xids {
+		splitXid := strings.Split(xid, ".")
+		if len(splitXid) != 2 {
+			return nil, fmt.Errorf("invalid xid: %s", xid)
+		}
+		namesByModule[splitXid[0]] = append(namesByModule[splitXid[0]], splitXid[1])
+	}
+	modules := slices.Sorted(maps.Keys(namesByModule))
+	conditions := make([]Domain, len(modules))
+	for i, module := range modules {
+		conditions[i] = And(Cond("module", OpEq, module), Cond("name", OpIn, namesByModule[module]))
+	}
+
+	modelData, err := c.SearchRead("ir.model.data", Or(conditions...), []string{"id", "module", "name", "model", "res_id"}, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	dataIdsByRecordId := map[int][]int{}
+	for _, data := range modelData {
+		xid := fmt.Sprintf("%v.%v", data["module"], data["name"])
+		if foundModel, _ := data["model"].(string); foundModel != model {
+			return nil, fmt.Errorf("model mismatch for XID %s: %s != %s", xid, model, foundModel)
+		}
+		recordId := helpers.JsonInt(data["res_id"])
+		idsByXid[xid] = recordId
+		dataIdsByRecordId[recordId] = append(dataIdsByRecordId[recordId], helpers.JsonInt(data["id"]))
+	}
+	if len(idsByXid) == 0 {
+		return idsByXid, nil
+	}
+
+	existingIds, err := c.SearchIds(model, []any{[]any{"id", "in", slices.Collect(maps.Keys(dataIdsByRecordId))}}, map[string]any{"active_test": false})
+	if err != nil {
+		return nil, fmt.Errorf("error checking records for XIDs of type %v\nERROR=%w", model, err)
+	}
+	staleDataIds := []int{}
+	for xid, recordId := range idsByXid {
+		if !slices.Contains(existingIds, recordId) {
+			// The XID (ir.model.data) remains in Odoo, but the related record was deleted
+			staleDataIds = append(staleDataIds, dataIdsByRecordId[recordId]...)
+			delete(idsByXid, xid)
+		}
+	}
+	if len(staleDataIds) != 0 {
+		slices.Sort(staleDataIds)
+		c.UnlinkMulti("ir.model.data", slices.Compact(staleDataIds), nil)
+	}
+
+	return idsByXid, nil
+}
+
+func (c *Client) AssignRecordXID(model string, id int, xid string) error {
 	splitXid := strings.Split(xid, ".")
 	if len(splitXid) != 2 {
 		return fmt.Errorf("invalid xid: %s", xid)
@@ -277,7 +295,7 @@ func AssignRecordXID(model string, id int, xid string) error {
 	xidModule := splitXid[0]
 	xidName := splitXid[1]
 
-	modelData, err := SearchRead(
+	modelData, err := c.SearchRead(
 		"ir.model.data",
 		[]any{
 			[]any{"module", "=", xidModule},
@@ -302,7 +320,7 @@ func AssignRecordXID(model string, id int, xid string) error {
 		return nil
 	}
 
-	_, err = Create("ir.model.data", map[string]any{
+	_, err = c.Create("ir.model.data", map[string]any{
 		"module": xidModule,
 		"name":   xidName,
 		"model":  model,
@@ -315,8 +333,15 @@ func AssignRecordXID(model string, id int, xid string) error {
 	return nil
 }
 
-func CreateMulti(model string, data []map[string]any, context map[string]any) ([]int, error) {
-	result, err := JsonRpcExecuteKw(model, "create", []any{data}, map[string]any{"context": context})
+func (c *Client) CreateMulti(model string, data []map[string]any, context map[string]any) ([]int, error) {
+	if c.config.ValidateFields {
+		for _, values := range data {
+			if err := c.ValidateValues(model, values); err != nil {
+				return []int{}, err
+			}
+		}
+	}
+	result, err := c.JsonRpcExecuteKw(model, "create", []any{data}, map[string]any{"context": context})
 	if err != nil {
 		return []int{}, err
 	}
@@ -338,25 +363,85 @@ func CreateMulti(model string, data []map[string]any, context map[string]any) ([
 	return idListInt, nil
 }
 
-func Create(model string, data map[string]any, context map[string]any) (int, error) {
+func (c *Client) Create(model string, data map[string]any, context map[string]any) (int, error) {
 	xid, hasXid := context["xid"]
-	delete(context, "xid")
-	idList, err := CreateMulti(model, []map[string]any{data}, context)
+	createContext := maps.Clone(context)
+	delete(createContext, "xid")
+	idList, err := c.CreateMulti(model, []map[string]any{data}, createContext)
 	if err != nil {
 		return 0, err
 	}
 	if hasXid {
-		err := AssignRecordXID(model, idList[0], xid.(string))
+		err := c.AssignRecordXID(model, idList[0], xid.(string))
 		if err != nil {
-			UnlinkMulti(model, idList, nil)
+			if unlinkErr := c.discardCreated(model, idList); unlinkErr != nil {
+				err = errors.Join(err, unlinkErr)
+			}
 			return 0, fmt.Errorf("error assigning XID %v after creation of %v (%v)\nERROR=%w", xid, model, idList[0], err)
 		}
 	}
 	return idList[0], nil
 }
 
-func WriteMulti(model string, ids []int, data map[string]any, context map[string]any) error {
-	result, err := JsonRpcExecuteKw(model, "write", []any{ids, data}, map[string]any{"context": context})
+// CreateOrGetByXID creates a record with its XID, or returns the ID of the record already
+// having it. When a concurrent call creates the same XID first, the record created here is
+// deleted and the other one is returned, so duplicate webhook deliveries never create
+// duplicate records. isNew is only true for the record actually kept.
+func (c *Client) CreateOrGetByXID(model string, xid string, data map[string]any, context map[string]any) (id int, isNew bool, err error) {
+	splitXid := strings.Split(xid, ".")
+	if len(splitXid) != 2 {
+		return 0, false, fmt.Errorf("invalid xid: %s", xid)
+	}
+	id, err = c.GetIDByXID(model, xid)
+	if err != nil || id != 0 {
+		return id, false, err
+	}
+
+	idList, err := c.CreateMulti(model, []map[string]any{data}, context)
+	if err != nil {
+		return 0, false, err
+	}
+	_, err = c.Create("ir.model.data", map[string]any{
+		"module": splitXid[0],
+		"name":   splitXid[1],
+		"model":  model,
+		"res_id": idList[0],
+	}, nil)
+	if err == nil {
+		return idList[0], true, nil
+	}
+
+	// Whatever the error, the record cannot be referenced without its XID
+	if unlinkErr := c.discardCreated(model, idList); unlinkErr != nil {
+		err = errors.Join(err, unlinkErr)
+	}
+	if !IsUniqueViolation(err) {
+		return 0, false, fmt.Errorf("error assigning XID %v after creation of %v (%v)\nERROR=%w", xid, model, idList[0], err)
+	}
+	id, err = c.GetIDByXID(model, xid)
+	if err != nil {
+		return 0, false, fmt.Errorf("error reading XID %v created concurrently\nERROR=%w", xid, err)
+	}
+	if id == 0 {
+		return 0, false, fmt.Errorf("record of XID %v created concurrently was deleted", xid)
+	}
+	return id, false, nil
+}
+
+// discardCreated deletes records just created whose XID could not be assigned. They were
+// never seen by users, so they are deleted whatever the unlink policy of model.
+func (c *Client) discardCreated(model string, ids []int) error {
+	_, err := c.JsonRpcExecuteKw(model, "unlink", []any{ids}, nil)
+	return err
+}
+
+func (c *Client) WriteMulti(model string, ids []int, data map[string]any, context map[string]any) error {
+	if c.config.ValidateFields {
+		if err := c.ValidateValues(model, data); err != nil {
+			return err
+		}
+	}
+	result, err := c.JsonRpcExecuteKw(model, "write", []any{ids, data}, map[string]any{"context": context})
 	if err != nil {
 		return err
 	}
@@ -369,12 +454,12 @@ func WriteMulti(model string, ids []int, data map[string]any, context map[string
 	return nil
 }
 
-func Write(model string, id int, data map[string]any, context map[string]any) error {
-	return WriteMulti(model, []int{id}, data, context)
+func (c *Client) Write(model string, id int, data map[string]any, context map[string]any) error {
+	return c.WriteMulti(model, []int{id}, data, context)
 }
 
-func SearchIds(model string, domain []any, context map[string]any) ([]int, error) {
-	result, err := SearchRead(model, domain, []string{"id"}, 0, context)
+func (c *Client) SearchIds(model string, domain []any, context map[string]any) ([]int, error) {
+	result, err := c.SearchRead(model, domain, []string{"id"}, 0, context)
 	if err != nil {
 		return []int{}, err
 	}
@@ -391,19 +476,22 @@ func SearchIds(model string, domain []any, context map[string]any) ([]int, error
 	return ids, nil
 }
 
-func SearchId(model string, domain []any, context map[string]any) (int, error) {
-	ids, err := SearchIds(model, domain, context)
+func (c *Client) SearchId(model string, domain []any, context map[string]any) (int, error) {
+	ids, err := c.SearchIds(model, domain, context)
 	if err != nil {
 		return 0, err
 	}
-	if len(ids) != 1 {
-		return 0, fmt.Errorf("search expected exactly 1 ID, got %v", len(ids))
+	if len(ids) == 0 {
+		return 0, fmt.Errorf("search expected exactly 1 ID, got 0: %w", ErrNotFound)
+	}
+	if len(ids) > 1 {
+		return 0, fmt.Errorf("search expected exactly 1 ID, got %v: %w", len(ids), ErrMultipleResults)
 	}
 	return ids[0], nil
 }
 
-func SearchFirstId(model string, domain []any, context map[string]any) (int, error) {
-	ids, err := SearchIds(model, domain, context)
+func (c *Client) SearchFirstId(model string, domain []any, context map[string]any) (int, error) {
+	ids, err := c.SearchIds(model, domain, context)
 	if err != nil {
 		return 0, err
 	}
@@ -413,8 +501,8 @@ func SearchFirstId(model string, domain []any, context map[string]any) (int, err
 	return ids[0], nil
 }
 
-func SearchWrite(model string, domain []any, data map[string]any, context map[string]any) error {
-	ids, err := SearchIds(model, domain, context)
+func (c *Client) SearchWrite(model string, domain []any, data map[string]any, context map[string]any) error {
+	ids, err := c.SearchIds(model, domain, context)
 	if err != nil {
 		return err
 	}
@@ -424,11 +512,11 @@ func SearchWrite(model string, domain []any, data map[string]any, context map[st
 		return nil
 	}
 
-	return WriteMulti(model, ids, data, context)
+	return c.WriteMulti(model, ids, data, context)
 }
 
-func SearchWriteOne(model string, domain []any, data map[string]any, context map[string]any) error {
-	ids, err := SearchIds(model, domain, context)
+func (c *Client) SearchWriteOne(model string, domain []any, data map[string]any, context map[string]any) error {
+	ids, err := c.SearchIds(model, domain, context)
 	if err != nil {
 		return err
 	}
@@ -437,11 +525,11 @@ func SearchWriteOne(model string, domain []any, data map[string]any, context map
 		return fmt.Errorf("search write expected exactly 1 match, got %d", len(ids))
 	}
 
-	return WriteMulti(model, ids, data, context)
+	return c.WriteMulti(model, ids, data, context)
 }
 
-func FindOrCreate(model string, domain []any, createData map[string]any, context map[string]any) (int, error) {
-	ids, err := SearchIds(model, domain, context)
+func (c *Client) FindOrCreate(model string, domain []any, createData map[string]any, context map[string]any) (int, error) {
+	ids, err := c.SearchIds(model, domain, context)
 	if err != nil {
 		return 0, err
 	}
@@ -453,20 +541,20 @@ func FindOrCreate(model string, domain []any, createData map[string]any, context
 	if len(ids) == 1 {
 		return ids[0], nil
 	}
-	id, err := Create(model, createData, context)
+	id, err := c.Create(model, createData, context)
 	if err != nil {
 		return 0, err
 	}
 	return id, nil
 }
 
-func FindFirstOrCreate(model string, domain []any, createData map[string]any, context map[string]any) (int, error) {
-	id, err := SearchFirstId(model, domain, context)
+func (c *Client) FindFirstOrCreate(model string, domain []any, createData map[string]any, context map[string]any) (int, error) {
+	id, err := c.SearchFirstId(model, domain, context)
 	if err != nil {
 		return 0, err
 	}
 	if id == 0 {
-		id, err = Create(model, createData, context)
+		id, err = c.Create(model, createData, context)
 		if err != nil {
 			return 0, err
 		}
@@ -474,71 +562,37 @@ func FindFirstOrCreate(model string, domain []any, createData map[string]any, co
 	return id, nil
 }
 
-func WriteOrCreate(model string, domain []any, data map[string]any, writeOnlyData map[string]any, createOnlyData map[string]any, context map[string]any) (int, error) {
+func (c *Client) WriteOrCreate(model string, domain []any, data map[string]any, writeOnlyData map[string]any, createOnlyData map[string]any, context map[string]any) (int, error) {
 	createData := map[string]any{}
 	maps.Copy(createData, data)
 	maps.Copy(createData, createOnlyData)
-	id, err := FindOrCreate(model, domain, createData, context)
+	id, err := c.FindOrCreate(model, domain, createData, context)
 	if err != nil {
 		return 0, err
 	}
 	if writeOnlyData != nil {
 		maps.Copy(data, writeOnlyData)
 	}
-	err = Write(model, id, data, context)
+	err = c.Write(model, id, data, context)
 	if err != nil {
 		return id, err
 	}
 	return id, nil
 }
 
-func UnlinkMulti(model string, ids []int, context map[string]any) error {
-	_, err := JsonRpcExecuteKw(model, "unlink", []any{ids}, map[string]any{"context": context})
+// UnlinkMulti deletes the records, or soft deletes them when an unlink policy is set for
+// model, see WithUnlinkPolicy.
+func (c *Client) UnlinkMulti(model string, ids []int, context map[string]any) error {
+	if _, found := c.UnlinkPolicy(model); found {
+		return c.SoftDeleteMulti(model, ids, context)
+	}
+	_, err := c.JsonRpcExecuteKw(model, "unlink", []any{ids}, map[string]any{"context": context})
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func Unlink(model string, id int, context map[string]any) error {
-	return UnlinkMulti(model, []int{id}, context)
-}
-
-func GetCountryAndStateIds(countryCode string, stateCode string) (int, int) {
-	var countryId, stateId int
-	country, err := SearchReadOne("res.country", []any{
-		[]any{"code", "=", countryCode},
-	}, []string{"id"}, nil)
-	if err == nil {
-		countryId = int(country["id"].(float64))
-		state, err := SearchReadOne("res.country.state", []any{
-			[]any{"country_id", "=", countryId},
-			[]any{"code", "=", stateCode},
-		}, []string{"id"}, nil)
-		if err == nil {
-			stateId = int(state["id"].(float64))
-		}
-	}
-	return countryId, stateId
-}
-
-func MapToDomain(dataMap map[string]any) []any {
-	count := 0
-	domainMap := map[int][]any{}
-	for key, val := range dataMap {
-		rVal := reflect.ValueOf(val)
-		switch rVal.Kind() {
-		case reflect.Int, reflect.Float32, reflect.Float64, reflect.Bool:
-			domainMap[count] = []any{key, "=", val}
-			count++
-		case reflect.String:
-			domainMap[count] = []any{key, "=ilike", val}
-			count++
-		}
-	}
-	domain := make([]any, count)
-	for i := range count {
-		domain[i] = domainMap[i]
-	}
-	return domain
+func (c *Client) Unlink(model string, id int, context map[string]any) error {
+	return c.UnlinkMulti(model, []int{id}, context)
 }
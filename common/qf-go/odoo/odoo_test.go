@@ -1,32 +1,217 @@
 package odoo
 
 import (
+	"reflect"
+	"strings"
 	"testing"
 )
 
-func TestTraverse(t *testing.T) {
-	res := MapToDomain(map[string]any{
+func TestMapToDomain(t *testing.T) {
+	res, err := MapToDomain(map[string]any{
 		"string": "string",
 		"int":    1,
 		"float":  20.0,
 		"bool":   false,
-		"map":    map[string]any{"x": "x"},
 		"slice":  []int{0},
+		"term":   Term{Operator: OpEq, Value: "exact"},
 	})
-	if len(res) != 4 {
-		t.Fatalf("expected 4 elements in domain, got %v: %v", len(res), res)
-	}
-	expected := [][]any{
-		{"string", "=ilike", "string"},
-		{"int", "=", 1},
-		{"float", "=", 20.0},
-		{"bool", "=", false},
-	}
-	for i := range 4 {
-		r := res[i].([]any)
-		e := expected[i]
-		if len(r) != 3 || r[0] != e[0] || r[1] != e[1] || r[2] != e[2] {
-			t.Fatalf("element %v is not a match, expected %v, got %v", i, expected, res)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := Domain{
+		[]any{"bool", "=", false},
+		[]any{"float", "=", 20.0},
+		[]any{"int", "=", 1},
+		[]any{"slice", "in", []int{0}},
+		[]any{"string", "=ilike", "string"},
+		[]any{"term", "=", "exact"},
+	}
+	if !reflect.DeepEqual(res, expected) {
+		t.Fatalf("domain is not a match, expected %v, got %v", expected, res)
+	}
+}
+
+func TestMapToDomain_Unsupported(t *testing.T) {
+	_, err := MapToDomain(map[string]any{
+		"map": map[string]any{"x": "x"},
+	})
+	if err == nil {
+		t.Fatalf("expected error for unsupported value type")
+	}
+}
+
+func TestGetIDsByXIDs(t *testing.T) {
+	requests := []rpcRequest{}
+	client := newTestClient(t, &requests, func(request rpcRequest) (int, any) {
+		args := request.Params["args"].([]any)
+		switch args[3].(string) + "/" + args[4].(string) {
+		case "ir.model.data/search_read":
+			return resultResponse([]any{
+				map[string]any{"id": 101.0, "module": "__export__", "name": "line_1", "model": "sale.order.line", "res_id": 1.0},
+				map[string]any{"id": 102.0, "module": "__export__", "name": "line_2", "model": "sale.order.line", "res_id": 2.0},
+				map[string]any{"id": 103.0, "module": "other", "name": "line_3", "model": "sale.order.line", "res_id": 3.0},
+			})
+		case "sale.order.line/search_read":
+			return resultResponse([]any{map[string]any{"id": 1.0}, map[string]any{"id": 3.0}})
+		}
+		return resultResponse(true)
+	})
+
+	ids, err := client.GetIDsByXIDs("sale.order.line", []string{"__export__.line_1", "__export__.line_2", "other.line_3", "other.missing"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]int{"__export__.line_1": 1, "other.line_3": 3}
+	if !reflect.DeepEqual(ids, expected) {
+		t.Fatalf("expected %v, got %v", expected, ids)
+	}
+	if len(requests) != 3 {
+		t.Fatalf("expected 3 requests (xids, existence, stale cleanup), got %d", len(requests))
+	}
+	domain := requests[0].Params["args"].([]any)[6].(map[string]any)["domain"].([]any)
+	if len(domain) != 7 || domain[0] != "|" {
+		t.Fatalf("expected a single OR domain grouped by module, got %v", domain)
+	}
+	unlink := requests[2].Params["args"].([]any)
+	if unlink[3] != "ir.model.data" || unlink[4] != "unlink" || !reflect.DeepEqual(unlink[5], []any{[]any{102.0}}) {
+		t.Fatalf("expected stale XID to be removed, got %v", unlink)
+	}
+}
+
+func TestGetIDsByXIDs_ModelMismatch(t *testing.T) {
+	client := newTestClient(t, nil, func(rpcRequest) (int, any) {
+		return resultResponse([]any{
+			map[string]any{"id": 101.0, "module": "__export__", "name": "x", "model": "res.partner", "res_id": 1.0},
+		})
+	})
+	if _, err := client.GetIDsByXIDs("sale.order.line", []string{"__export__.x"}); err == nil {
+		t.Fatalf("expected model mismatch error")
+	}
+}
+
+func TestCreateOrGetByXID_Race(t *testing.T) {
+	requests := []rpcRequest{}
+	winnerCreated := false
+	client := newTestClient(t, &requests, func(request rpcRequest) (int, any) {
+		args := request.Params["args"].([]any)
+		switch args[3].(string) + "/" + args[4].(string) {
+		case "ir.model.data/search_read":
+			if !winnerCreated {
+				return resultResponse([]any{})
+			}
+			return resultResponse([]any{map[string]any{"id": 101.0, "module": "__export__", "name": "shopify_order_1", "model": "sale.order", "res_id": 7.0}})
+		case "sale.order/create":
+			return resultResponse([]any{8.0})
+		case "ir.model.data/create":
+			// A concurrent delivery assigned the XID first
+			winnerCreated = true
+			return 200, map[string]any{"jsonrpc": "2.0", "id": 1, "error": map[string]any{
+				"code":    200.0,
+				"message": "Odoo Server Error",
+				"data": map[string]any{
+					"name":    ExceptionUniqueViolation,
+					"message": "duplicate key value violates unique constraint \"ir_model_data_module_name_uniq_index\"",
+				},
+			}}
+		case "sale.order/search_count":
+			return resultResponse(1.0)
+		case "sale.order/search_read":
+			return resultResponse([]any{map[string]any{"id": 7.0}})
 		}
+		return resultResponse(true)
+	})
+
+	id, isNew, err := client.CreateOrGetByXID("sale.order", "__export__.shopify_order_1", map[string]any{"partner_id": 1}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if id != 7 || isNew {
+		t.Fatalf("expected the concurrently created order 7, got %v, %v", id, isNew)
+	}
+	unlinked := false
+	for _, request := range requests {
+		args := request.Params["args"].([]any)
+		if args[3] == "sale.order" && args[4] == "unlink" && reflect.DeepEqual(args[5], []any{[]any{8.0}}) {
+			unlinked = true
+		}
+	}
+	if !unlinked {
+		t.Fatalf("expected the duplicate order to be deleted")
+	}
+
+	id, isNew, err = client.CreateOrGetByXID("sale.order", "__export__.shopify_order_1", map[string]any{"partner_id": 1}, nil)
+	if err != nil || id != 7 || isNew {
+		t.Fatalf("expected existing order 7 without creation, got %v, %v, %v", id, isNew, err)
+	}
+}
+
+func TestCreateOrGetByXID_UnlinkPolicy(t *testing.T) {
+	requests := []rpcRequest{}
+	client := newTestClient(t, &requests, func(request rpcRequest) (int, any) {
+		args := request.Params["args"].([]any)
+		switch args[3].(string) + "/" + args[4].(string) {
+		case "ir.model.data/search_read":
+			return resultResponse([]any{})
+		case "sale.order/create":
+			return resultResponse([]any{8.0})
+		case "ir.model.data/create":
+			return 200, map[string]any{"jsonrpc": "2.0", "id": 1, "error": map[string]any{
+				"code":    200.0,
+				"message": "Odoo Server Error",
+				"data":    map[string]any{"name": "odoo.exceptions.AccessError", "message": "Access denied"},
+			}}
+		}
+		return resultResponse(true)
+	}).WithUnlinkPolicy("sale.order", CancelPolicy)
+
+	if _, _, err := client.CreateOrGetByXID("sale.order", "__export__.shopify_order_1", map[string]any{"partner_id": 1}, nil); err == nil {
+		t.Fatalf("expected error assigning the XID")
+	}
+	// The duplicate was never seen by users, it is deleted instead of cancelled
+	methods := []string{}
+	for _, request := range requests {
+		args := request.Params["args"].([]any)
+		if args[3] == "sale.order" {
+			methods = append(methods, args[4].(string))
+		}
+	}
+	if !reflect.DeepEqual(methods, []string{"create", "unlink"}) {
+		t.Fatalf("expected the order without XID to be deleted, got %v", methods)
+	}
+}
+
+func TestCreate_XIDFailure(t *testing.T) {
+	requests := []rpcRequest{}
+	client := newTestClient(t, &requests, func(request rpcRequest) (int, any) {
+		args := request.Params["args"].([]any)
+		switch args[3].(string) + "/" + args[4].(string) {
+		case "ir.model.data/search_read":
+			return resultResponse([]any{})
+		case "sale.order/create":
+			return resultResponse([]any{8.0})
+		case "ir.model.data/create", "sale.order/unlink":
+			return 200, map[string]any{"jsonrpc": "2.0", "id": 1, "error": map[string]any{
+				"code":    200.0,
+				"message": "Odoo Server Error",
+				"data":    map[string]any{"name": "odoo.exceptions.AccessError", "message": "Access denied to " + args[3].(string)},
+			}}
+		}
+		return resultResponse(true)
+	}).WithUnlinkPolicy("sale.order", CancelPolicy)
+
+	_, err := client.Create("sale.order", map[string]any{"partner_id": 1}, map[string]any{"xid": "__export__.shopify_order_1"})
+	if err == nil || !strings.Contains(err.Error(), "Access denied to ir.model.data") || !strings.Contains(err.Error(), "Access denied to sale.order") {
+		t.Fatalf("expected the XID and unlink errors, got %v", err)
+	}
+	// The order was never seen by users, it is deleted instead of cancelled
+	methods := []string{}
+	for _, request := range requests {
+		args := request.Params["args"].([]any)
+		if args[3] == "sale.order" {
+			methods = append(methods, args[4].(string))
+		}
+	}
+	if !reflect.DeepEqual(methods, []string{"create", "unlink"}) {
+		t.Fatalf("expected the order without XID to be deleted, got %v", methods)
 	}
 }
@@ -0,0 +1,231 @@
+package odootest
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// search returns the records of model matching domain, honouring the order, offset and
+// limit of kwargs and the active_test of context.
+func (s *Server) search(model string, domain []any, kwargs map[string]any, context map[string]any) ([]Record, error) {
+	activeTest := context["active_test"] != false && !domainUses(domain, "active")
+	records := []Record{}
+	for _, id := range slices.Sorted(maps.Keys(s.records[model])) {
+		record := s.records[model][id]
+		if activeTest && record["active"] == false {
+			continue
+		}
+		matches, err := s.match(model, record, domain)
+		if err != nil {
+			return nil, Exception("builtins.ValueError", fmt.Sprintf("Invalid domain %v: %v", domain, err))
+		}
+		if matches {
+			records = append(records, record)
+		}
+	}
+
+	if order, _ := kwargs["order"].(string); order != "" {
+		s.sort(model, records, order)
+	}
+	if offset, _ := kwargs["offset"].(float64); offset > 0 {
+		records = records[min(int(offset), len(records)):]
+	}
+	if limit, _ := kwargs["limit"].(float64); limit > 0 {
+		records = records[:min(int(limit), len(records))]
+	}
+	return records, nil
+}
+
+func domainUses(domain []any, field string) bool {
+	for _, term := range domain {
+		if leaf, ok := term.([]any); ok && len(leaf) == 3 && leaf[0] == field {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) sort(model string, records []Record, order string) {
+	slices.SortStableFunc(records, func(a, b Record) int {
+		for _, part := range strings.Split(order, ",") {
+			field, direction, _ := strings.Cut(strings.TrimSpace(part), " ")
+			result := compare(s.value(model, a, field), s.value(model, b, field))
+			if strings.EqualFold(strings.TrimSpace(direction), "desc") {
+				result = -result
+			}
+			if result != 0 {
+				return result
+			}
+		}
+		return 0
+	})
+}
+
+func (s *Server) match(model string, record Record, domain []any) (bool, error) {
+	result := true
+	for i := 0; i < len(domain); {
+		matches, next, err := s.evaluate(model, record, domain, i)
+		if err != nil {
+			return false, err
+		}
+		result = result && matches
+		i = next
+	}
+	return result, nil
+}
+
+// evaluate evaluates the expression of domain starting at index i, and returns the
+// index after it.
+func (s *Server) evaluate(model string, record Record, domain []any, i int) (bool, int, error) {
+	if i >= len(domain) {
+		return false, 0, fmt.Errorf("missing operand")
+	}
+	switch domain[i] {
+	case "&", "|":
+		left, next, err := s.evaluate(model, record, domain, i+1)
+		if err != nil {
+			return false, 0, err
+		}
+		right, next, err := s.evaluate(model, record, domain, next)
+		if err != nil {
+			return false, 0, err
+		}
+		if domain[i] == "&" {
+			return left && right, next, nil
+		}
+		return left || right, next, nil
+	case "!":
+		matches, next, err := s.evaluate(model, record, domain, i+1)
+		return !matches, next, err
+	}
+	leaf, ok := domain[i].([]any)
+	if !ok || len(leaf) != 3 {
+		return false, 0, fmt.Errorf("invalid term %v", domain[i])
+	}
+	matches, err := s.evaluateLeaf(model, record, leaf)
+	return matches, i + 1, err
+}
+
+func (s *Server) evaluateLeaf(model string, record Record, leaf []any) (bool, error) {
+	if constant, ok := leaf[0].(float64); ok && leaf[1] == "=" && leaf[2] == 1.0 {
+		// TRUE_LEAF or FALSE_LEAF
+		return constant == 1, nil
+	}
+	field, _ := leaf[0].(string)
+	operator, _ := leaf[1].(string)
+	if field == "" || strings.Contains(field, ".") {
+		return false, fmt.Errorf("unsupported field %v", leaf[0])
+	}
+	stored := s.value(model, record, field)
+	value := leaf[2]
+
+	switch operator {
+	case "=", "child_of", "parent_of":
+		return equals(stored, value), nil
+	case "!=":
+		return !equals(stored, value), nil
+	case "=?":
+		return isFalse(value) || equals(stored, value), nil
+	case ">", ">=", "<", "<=":
+		if isFalse(stored) {
+			return false, nil
+		}
+		result := compare(stored, value)
+		return operator == ">" && result > 0 || operator == ">=" && result >= 0 ||
+			operator == "<" && result < 0 || operator == "<=" && result <= 0, nil
+	case "like", "ilike", "not like", "not ilike", "=like", "=ilike":
+		storedStr, _ := stored.(string)
+		pattern := fmt.Sprint(value)
+		if !strings.HasPrefix(operator, "=") {
+			pattern = "%" + pattern + "%"
+		}
+		matches := like(storedStr, pattern, strings.HasSuffix(operator, "ilike"))
+		if strings.HasPrefix(operator, "not") {
+			return !matches, nil
+		}
+		return matches, nil
+	case "in", "not in":
+		values, ok := value.([]any)
+		if !ok {
+			return false, fmt.Errorf("operator %v expects a list, got %v", operator, value)
+		}
+		matches := slices.ContainsFunc(values, func(value any) bool { return equals(stored, value) })
+		if operator == "not in" {
+			return !matches, nil
+		}
+		return matches, nil
+	}
+	return false, fmt.Errorf("unsupported operator %v", leaf[1])
+}
+
+func isFalse(value any) bool {
+	list, isList := value.([]any)
+	return value == nil || value == false || isList && len(list) == 0
+}
+
+// equals compares a stored value with a domain value. Lists (x2many) match if they
+// contain the value, and false matches unset values.
+func equals(stored any, value any) bool {
+	if isFalse(value) {
+		return isFalse(stored)
+	}
+	if list, isList := stored.([]any); isList {
+		return slices.ContainsFunc(list, func(item any) bool { return equals(item, value) })
+	}
+	return compare(stored, value) == 0 && !isFalse(stored)
+}
+
+func compare(a, b any) int {
+	switch a := a.(type) {
+	case float64:
+		if b, ok := b.(float64); ok {
+			return cmp.Compare(a, b)
+		}
+	case string:
+		if b, ok := b.(string); ok {
+			return strings.Compare(a, b)
+		}
+	case bool:
+		if b, ok := b.(bool); ok && a == b {
+			return 0
+		}
+	}
+	// Different types, unset values first
+	if isFalse(a) && !isFalse(b) {
+		return -1
+	}
+	if !isFalse(a) && isFalse(b) {
+		return 1
+	}
+	if isFalse(a) && isFalse(b) {
+		return 0
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+// like matches value against a SQL LIKE pattern with the % and _ wildcards.
+func like(value, pattern string, insensitive bool) bool {
+	expression := strings.Builder{}
+	if insensitive {
+		expression.WriteString("(?is)")
+	} else {
+		expression.WriteString("(?s)")
+	}
+	expression.WriteString("^")
+	for _, char := range pattern {
+		switch char {
+		case '%':
+			expression.WriteString(".*")
+		case '_':
+			expression.WriteString(".")
+		default:
+			expression.WriteString(regexp.QuoteMeta(string(char)))
+		}
+	}
+	expression.WriteString("$")
+	return regexp.MustCompile(expression.String()).MatchString(value)
+}
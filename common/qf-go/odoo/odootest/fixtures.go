@@ -0,0 +1,90 @@
+package odootest
+
+import (
+	"qf/go/odoo"
+)
+
+// SeedCompanies seeds the QF and FM companies with the IDs of odoo.CompanyQF and
+// odoo.CompanyFM.
+func (s *Server) SeedCompanies() {
+	s.Seed("res.company",
+		Record{"id": odoo.CompanyQF, "name": "QF"},
+		Record{"id": odoo.CompanyFM, "name": "FM"},
+	)
+}
+
+// SeedCountries seeds Canada with its provinces and territories, and the United States
+// with a few states.
+func (s *Server) SeedCountries() {
+	ids := s.Seed("res.country",
+		Record{"name": "Canada", "code": "CA"},
+		Record{"name": "United States", "code": "US"},
+	)
+	canada, unitedStates := ids[0], ids[1]
+	for code, name := range map[string]string{
+		"AB": "Alberta", "BC": "British Columbia", "MB": "Manitoba", "NB": "New Brunswick",
+		"NL": "Newfoundland and Labrador", "NS": "Nova Scotia", "NT": "Northwest Territories",
+		"NU": "Nunavut", "ON": "Ontario", "PE": "Prince Edward Island", "QC": "Quebec",
+		"SK": "Saskatchewan", "YT": "Yukon",
+	} {
+		s.Seed("res.country.state", Record{"name": name, "code": code, "country_id": canada})
+	}
+	for code, name := range map[string]string{"CA": "California", "NY": "New York", "WA": "Washington"} {
+		s.Seed("res.country.state", Record{"name": name, "code": code, "country_id": unitedStates})
+	}
+}
+
+// SeedCurrencies seeds CAD and USD.
+func (s *Server) SeedCurrencies() {
+	s.Seed("res.currency",
+		Record{"name": "CAD", "symbol": "$"},
+		Record{"name": "USD", "symbol": "$"},
+	)
+}
+
+// SeedProducts seeds a product for each SKU.
+func (s *Server) SeedProducts(skus ...string) map[string]int {
+	idsBySku := map[string]int{}
+	for _, sku := range skus {
+		idsBySku[sku] = s.Seed("product.product", Record{"name": sku, "default_code": sku})[0]
+	}
+	return idsBySku
+}
+
+// SeedTaxes seeds the Canadian sales taxes for both companies, named as the Shopify tax
+// lines are mapped (e.g. "HST 13%").
+func (s *Server) SeedTaxes() {
+	taxes := []struct {
+		name   string
+		amount float64
+	}{{"GST 5%", 5}, {"HST 13%", 13}, {"HST 15%", 15}, {"PST 7%", 7}, {"QST 9.975%", 9.975}}
+	for _, companyId := range []int{odoo.CompanyQF, odoo.CompanyFM} {
+		for _, tax := range taxes {
+			s.Seed("account.tax", Record{
+				"name":         tax.name,
+				"description":  tax.name,
+				"amount":       tax.amount,
+				"amount_type":  "percent",
+				"type_tax_use": "sale",
+				"company_id":   companyId,
+			})
+		}
+	}
+}
+
+// SeedPaymentAcquirers seeds the Shopify payment acquirer of both companies.
+func (s *Server) SeedPaymentAcquirers() {
+	for _, companyId := range []int{odoo.CompanyQF, odoo.CompanyFM} {
+		s.Seed("payment.acquirer", Record{"name": "Shopify", "company_id": companyId})
+	}
+}
+
+// SeedFixtures seeds all the reference data above, with the shipping products.
+func (s *Server) SeedFixtures() {
+	s.SeedCompanies()
+	s.SeedCountries()
+	s.SeedCurrencies()
+	s.SeedProducts(odoo.ShippingSku, odoo.TwoshipSku)
+	s.SeedTaxes()
+	s.SeedPaymentAcquirers()
+}
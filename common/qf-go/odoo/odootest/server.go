@@ -0,0 +1,804 @@
+// Package odootest provides an in-memory Odoo JSON-RPC server, so code using the odoo
+// package can be tested without a live database.
+package odootest
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"qf/go/odoo"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Record is a stored record, with values as they would be decoded from JSON (numbers
+// are float64, many2one fields hold the ID and x2many fields a list of IDs).
+type Record map[string]any
+
+// Call is an execute_kw call received by the server.
+type Call struct {
+	Model  string
+	Method string
+	Args   []any
+	Kwargs map[string]any
+}
+
+// Method implements a model method not handled by the server, such as action_confirm.
+// It is called without holding the server lock, so it can use Write, Records...
+type Method func(ids []int, args []any, kwargs map[string]any) (any, error)
+
+type one2Many struct {
+	comodel string
+	inverse string
+}
+
+// Server is an Odoo JSON-RPC endpoint storing records in memory. It supports
+// search_read, search, search_count, read, create, write and unlink on any model,
+// x2many command tuples, and the uniqueness of ir.model.data (module, name).
+// message_post stores the message as a mail.message record, and fields_get answers the
+// fields declared with SetFields, or else those renamed across versions, following
+// Version. Users authenticate with
+// common.authenticate, or /web/session/authenticate then /web/dataset/call_kw.
+type Server struct {
+	*httptest.Server
+	Version  string // Answered to common.version, "15.0" by default
+	Login    string // Accepted with Password by authenticate as UserID
+	Password string
+
+	mu          sync.Mutex
+	records     map[string]map[int]Record
+	lastID      map[string]int
+	relations   map[string]map[string]string
+	one2many    map[string]map[string]one2Many
+	defaults    map[string]func(id int, values Record)
+	fields      map[string]map[string]any
+	methods     map[string]Method
+	calls       []Call
+	sessions    map[string]bool
+	lastSession int
+}
+
+// Many2one comodels used when none was declared with Many2One, by field name.
+var defaultRelations = map[string]string{
+	"partner_id":            "res.partner",
+	"partner_invoice_id":    "res.partner",
+	"partner_shipping_id":   "res.partner",
+	"commercial_partner_id": "res.partner",
+	"parent_id":             "res.partner",
+	"company_id":            "res.company",
+	"country_id":            "res.country",
+	"state_id":              "res.country.state",
+	"currency_id":           "res.currency",
+	"product_id":            "product.product",
+	"order_id":              "sale.order",
+	"user_id":               "res.users",
+	"team_id":               "crm.team",
+	"carrier_id":            "delivery.carrier",
+	"source_id":             "utm.source",
+	"acquirer_id":           "payment.acquirer",
+	"provider_id":           "payment.provider",
+}
+
+// NewServer starts a server closed at the end of the test. sale.order records get a
+// name, the draft state and their commercial partner on creation, and action_confirm
+// moves them to the sale state.
+func NewServer(t testing.TB) *Server {
+	s := &Server{
+		Version:   "15.0",
+		Login:     "admin",
+		Password:  "admin",
+		sessions:  map[string]bool{},
+		records:   map[string]map[int]Record{},
+		lastID:    map[string]int{},
+		relations: map[string]map[string]string{},
+		one2many:  map[string]map[string]one2Many{},
+		defaults:  map[string]func(int, Record){},
+		fields:    map[string]map[string]any{},
+		methods:   map[string]Method{},
+	}
+	s.One2Many("sale.order", "order_line", "sale.order.line", "order_id")
+	s.SetDefaults("sale.order", func(id int, values Record) {
+		setDefault(values, "name", fmt.Sprintf("S%05d", id))
+		setDefault(values, "state", "draft")
+		setDefault(values, "commercial_partner_id", values["partner_id"])
+	})
+	s.Handle("sale.order", "action_confirm", func(ids []int, args []any, kwargs map[string]any) (any, error) {
+		return true, s.Write("sale.order", ids, Record{"state": "sale"})
+	})
+
+	s.Server = httptest.NewServer(s)
+	t.Cleanup(s.Close)
+	return s
+}
+
+func setDefault(values Record, field string, value any) {
+	if _, found := values[field]; !found && value != nil {
+		values[field] = value
+	}
+}
+
+// UserID is the ID of the user authenticated by the server.
+const UserID = 2
+
+// Client returns a client for the server, without retries.
+func (s *Server) Client(t testing.TB) *odoo.Client {
+	t.Helper()
+	client, err := odoo.NewClient(odoo.Config{
+		URL:      s.URL,
+		DB:       "odootest",
+		UserID:   UserID,
+		Password: s.Password,
+		Retry:    odoo.RetryPolicy{MaxAttempts: 1},
+	})
+	if err != nil {
+		t.Fatalf("error creating client for the fake Odoo server: %v", err)
+	}
+	return client
+}
+
+// SetDefault makes the server the target of the package-level odoo functions until the
+// end of the test, and returns its client.
+func (s *Server) SetDefault(t testing.TB) *odoo.Client {
+	client := s.Client(t)
+	t.Cleanup(odoo.SetDefault(client))
+	return client
+}
+
+// Many2One declares the comodel of a many2one field, read as [id, display name].
+func (s *Server) Many2One(model, field, comodel string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.relations[model] == nil {
+		s.relations[model] = map[string]string{}
+	}
+	s.relations[model][field] = comodel
+}
+
+// One2Many declares a one2many field, computed from the inverse many2one of comodel.
+func (s *Server) One2Many(model, field, comodel, inverse string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.one2many[model] == nil {
+		s.one2many[model] = map[string]one2Many{}
+	}
+	s.one2many[model][field] = one2Many{comodel: comodel, inverse: inverse}
+	if s.relations[comodel] == nil {
+		s.relations[comodel] = map[string]string{}
+	}
+	s.relations[comodel][inverse] = model
+}
+
+// SetDefaults sets a function filling the values of model's new records.
+func (s *Server) SetDefaults(model string, defaults func(id int, values Record)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.defaults[model] = defaults
+}
+
+// Handle implements method on model, replacing any previous implementation.
+func (s *Server) Handle(model, method string, fn Method) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.methods[model+"."+method] = fn
+}
+
+// Calls returns the execute_kw calls received so far.
+func (s *Server) Calls() []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.calls)
+}
+
+// Seed stores records as they are, without defaults nor commands. Records with an "id"
+// keep it, the others get the next free ID.
+func (s *Server) Seed(model string, records ...Record) []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ids := make([]int, len(records))
+	for i, record := range records {
+		values := normalize(record)
+		id := s.nextID(model, values["id"])
+		values["id"] = float64(id)
+		s.table(model)[id] = values
+		ids[i] = id
+	}
+	return ids
+}
+
+// SeedXID assigns xid ("module.name") to the record of model with id.
+func (s *Server) SeedXID(xid, model string, id int) {
+	module, name, _ := strings.Cut(xid, ".")
+	s.Seed("ir.model.data", Record{"module": module, "name": name, "model": model, "res_id": id})
+}
+
+// Records returns a copy of the records of model as stored, ordered by ID.
+func (s *Server) Records(model string) []Record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	records := []Record{}
+	for _, id := range slices.Sorted(maps.Keys(s.records[model])) {
+		records = append(records, maps.Clone(s.records[model][id]))
+	}
+	return records
+}
+
+// Record returns a copy of the record of model with id as stored, or nil.
+func (s *Server) Record(model string, id int) Record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return maps.Clone(s.records[model][id])
+}
+
+// XID returns the model and ID of the record with xid, or "" and 0.
+func (s *Server) XID(xid string) (string, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	module, name, _ := strings.Cut(xid, ".")
+	for _, data := range s.records["ir.model.data"] {
+		if data["module"] == module && data["name"] == name {
+			model, _ := data["model"].(string)
+			resId, _ := data["res_id"].(float64)
+			return model, int(resId)
+		}
+	}
+	return "", 0
+}
+
+// Write updates the records of model with ids, applying x2many commands.
+func (s *Server) Write(model string, ids []int, values Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := s.write(model, ids, normalize(values))
+	return err
+}
+
+// Exception returns an error sent to the client as the Odoo exception name, e.g.
+// odoo.ExceptionUser.
+func Exception(name, message string) error {
+	return &odoo.RPCError{Code: 200, Message: "Odoo Server Error", Name: name, Detail: message}
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	request := struct {
+		ID     any `json:"id"`
+		Params struct {
+			Service  string         `json:"service"`
+			Method   string         `json:"method"`
+			Args     []any          `json:"args"`
+			Model    string         `json:"model"`    // call_kw
+			Kwargs   map[string]any `json:"kwargs"`   // call_kw
+			DB       string         `json:"db"`       // session/authenticate
+			Login    string         `json:"login"`    // session/authenticate
+			Password string         `json:"password"` // session/authenticate
+		} `json:"params"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var result any
+	var err error
+	switch {
+	case r.URL.Path == "/web/session/authenticate":
+		if request.Params.Login != s.Login || request.Params.Password != s.Password {
+			err = Exception(odoo.ExceptionAccessDenied, "Access Denied")
+			break
+		}
+		s.mu.Lock()
+		s.lastSession++
+		sessionId := fmt.Sprintf("session-%d", s.lastSession)
+		s.sessions[sessionId] = true
+		s.mu.Unlock()
+		http.SetCookie(w, &http.Cookie{Name: "session_id", Value: sessionId, Path: "/"})
+		result = map[string]any{"uid": UserID, "db": request.Params.DB, "username": s.Login}
+	case strings.HasPrefix(r.URL.Path, "/web/dataset/call_kw"):
+		if !s.validSession(r) {
+			err = &odoo.RPCError{Code: 100, Message: "Odoo Session Expired", Name: odoo.ExceptionSessionExpired, Detail: "Session expired"}
+			break
+		}
+		methodArgs, kwargs := request.Params.Args, request.Params.Kwargs
+		if methodArgs == nil {
+			methodArgs = []any{}
+		}
+		if kwargs == nil {
+			kwargs = map[string]any{}
+		}
+		result, err = s.execute(request.Params.Model, request.Params.Method, methodArgs, kwargs)
+	case request.Params.Service == "common" && request.Params.Method == "authenticate" && len(request.Params.Args) >= 3:
+		result = false
+		if request.Params.Args[1] == s.Login && request.Params.Args[2] == s.Password {
+			result = UserID
+		}
+	case request.Params.Service == "common" && request.Params.Method == "version":
+		version, _ := odoo.ParseVersion(s.Version)
+		result = map[string]any{
+			"server_version":      s.Version,
+			"server_serie":        s.Version,
+			"server_version_info": []any{version.Major, version.Minor, 0, "final", 0, ""},
+			"protocol_version":    1,
+		}
+	case request.Params.Service == "object" && request.Params.Method == "execute_kw" && len(request.Params.Args) >= 5:
+		args := request.Params.Args
+		model, _ := args[3].(string)
+		method, _ := args[4].(string)
+		methodArgs, kwargs := []any{}, map[string]any{}
+		if len(args) > 5 {
+			methodArgs, _ = args[5].([]any)
+		}
+		if len(args) > 6 {
+			kwargs, _ = args[6].(map[string]any)
+		}
+		result, err = s.execute(model, method, methodArgs, kwargs)
+	default:
+		err = Exception("builtins.NotImplementedError", fmt.Sprintf("%s.%s is not implemented by odootest", request.Params.Service, request.Params.Method))
+	}
+
+	response := map[string]any{"jsonrpc": "2.0", "id": request.ID, "result": result}
+	if err != nil {
+		rpcError, ok := err.(*odoo.RPCError)
+		if !ok {
+			rpcError = &odoo.RPCError{Code: 200, Message: "Odoo Server Error", Name: "builtins.Exception", Detail: err.Error()}
+		}
+		delete(response, "result")
+		response["error"] = map[string]any{
+			"code":    rpcError.Code,
+			"message": rpcError.Message,
+			"data":    map[string]any{"name": rpcError.Name, "message": rpcError.Detail, "debug": rpcError.Debug, "arguments": []any{rpcError.Detail}},
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (s *Server) validSession(r *http.Request) bool {
+	cookie, err := r.Cookie("session_id")
+	if err != nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sessions[cookie.Value]
+}
+
+// ExpireSessions logs out all the sessions, as when they expire in Odoo.
+func (s *Server) ExpireSessions() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions = map[string]bool{}
+}
+
+func (s *Server) execute(model, method string, args []any, kwargs map[string]any) (any, error) {
+	s.mu.Lock()
+	s.calls = append(s.calls, Call{Model: model, Method: method, Args: args, Kwargs: kwargs})
+	custom := s.methods[model+"."+method]
+	s.mu.Unlock()
+	if custom != nil {
+		ids := []int{}
+		if len(args) != 0 {
+			ids = toIDs(args[0])
+		}
+		return custom(ids, args, kwargs)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	context, _ := kwargs["context"].(map[string]any)
+	switch method {
+	case "search_read":
+		domain, fields := argOrKwarg(args, kwargs, 0, "domain"), argOrKwarg(args, kwargs, 1, "fields")
+		records, err := s.search(model, toList(domain), kwargs, context)
+		if err != nil {
+			return nil, err
+		}
+		result := make([]any, len(records))
+		for i, record := range records {
+			result[i] = s.read(model, record, toStrings(fields))
+		}
+		return result, nil
+	case "search":
+		records, err := s.search(model, toList(argOrKwarg(args, kwargs, 0, "domain")), kwargs, context)
+		if err != nil {
+			return nil, err
+		}
+		ids := make([]any, len(records))
+		for i, record := range records {
+			ids[i] = record["id"]
+		}
+		return ids, nil
+	case "search_count":
+		records, err := s.search(model, toList(argOrKwarg(args, kwargs, 0, "domain")), map[string]any{}, context)
+		return len(records), err
+	case "read":
+		fields := toStrings(argOrKwarg(args, kwargs, 1, "fields"))
+		result := []any{}
+		for _, id := range toIDs(argOrKwarg(args, kwargs, 0, "ids")) {
+			if record, found := s.records[model][id]; found {
+				result = append(result, s.read(model, record, fields))
+			}
+		}
+		return result, nil
+	case "create":
+		valuesList, isList := argOrKwarg(args, kwargs, 0, "vals_list").([]any)
+		if !isList {
+			valuesList = []any{argOrKwarg(args, kwargs, 0, "vals_list")}
+		}
+		ids := make([]any, len(valuesList))
+		for i, values := range valuesList {
+			valuesMap, _ := values.(map[string]any)
+			id, err := s.create(model, valuesMap)
+			if err != nil {
+				return nil, err
+			}
+			ids[i] = id
+		}
+		if !isList {
+			return ids[0], nil
+		}
+		return ids, nil
+	case "write":
+		values, _ := argOrKwarg(args, kwargs, 1, "vals").(map[string]any)
+		return s.write(model, toIDs(argOrKwarg(args, kwargs, 0, "ids")), values)
+	case "unlink":
+		return s.unlink(model, toIDs(argOrKwarg(args, kwargs, 0, "ids")))
+	case "fields_get":
+		return s.fieldsGet(model), nil
+	case "message_post":
+		ids := toIDs(argOrKwarg(args, kwargs, 0, "ids"))
+		if len(ids) != 1 {
+			return nil, Exception("builtins.ValueError", fmt.Sprintf("Expected singleton: %s(%v)", model, ids))
+		}
+		message := maps.Clone(kwargs)
+		delete(message, "context")
+		message["model"], message["res_id"] = model, float64(ids[0])
+		return s.create("mail.message", message)
+	}
+	return nil, Exception("builtins.AttributeError", fmt.Sprintf("The method '%s' does not exist on the model '%s'", method, model))
+}
+
+// SetFields declares the fields answered by fields_get for model, by name and type.
+func (s *Server) SetFields(model string, fields map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fields[model] = map[string]any{}
+	for name, fieldType := range fields {
+		s.fields[model][name] = map[string]any{"type": fieldType, "string": name}
+	}
+}
+
+// fieldsGet answers the declared fields of model, or else the fields of odoo.FieldAliases
+// as the server version has them.
+func (s *Server) fieldsGet(model string) map[string]any {
+	if fields, found := s.fields[model]; found {
+		return fields
+	}
+	fields := map[string]any{}
+	version, _ := odoo.ParseVersion(s.Version)
+	for field, alias := range odoo.FieldAliases[model] {
+		name := field
+		if since := versionRenames[model+"."+field]; since != 0 && version.Major >= since {
+			name = alias
+		}
+		fields[name] = map[string]any{"type": "char", "string": name}
+	}
+	return fields
+}
+
+// Versions renaming the fields of odoo.FieldAliases, the others are kept on any version.
+var versionRenames = map[string]int{
+	"payment.transaction.acquirer_id":        16,
+	"payment.transaction.acquirer_reference": 16,
+}
+
+func (s *Server) table(model string) map[int]Record {
+	if s.records[model] == nil {
+		s.records[model] = map[int]Record{}
+	}
+	return s.records[model]
+}
+
+func (s *Server) nextID(model string, requested any) int {
+	id := s.lastID[model] + 1
+	if requestedID, ok := requested.(float64); ok && requestedID > 0 {
+		id = int(requestedID)
+	}
+	s.lastID[model] = max(s.lastID[model], id)
+	return id
+}
+
+func (s *Server) create(model string, values map[string]any) (float64, error) {
+	if model == "ir.model.data" {
+		for _, data := range s.records[model] {
+			if data["module"] == values["module"] && data["name"] == values["name"] {
+				return 0, Exception("psycopg2.errors.UniqueViolation", fmt.Sprintf(
+					"duplicate key value violates unique constraint \"ir_model_data_module_name_uniq_index\"\nDETAIL:  Key (module, name)=(%v, %v) already exists.", values["module"], values["name"]))
+			}
+		}
+	}
+	id := s.nextID(model, nil)
+	record := Record{"id": float64(id)}
+	if defaults := s.defaults[model]; defaults != nil {
+		defaultValues := Record(maps.Clone(values))
+		defaults(id, defaultValues)
+		values = defaultValues
+	}
+	s.table(model)[id] = record
+	if _, err := s.write(model, []int{id}, values); err != nil {
+		delete(s.records[model], id)
+		return 0, err
+	}
+	return float64(id), nil
+}
+
+func (s *Server) write(model string, ids []int, values map[string]any) (bool, error) {
+	for _, id := range ids {
+		if _, found := s.records[model][id]; !found {
+			return false, Exception(odoo.ExceptionMissing, fmt.Sprintf("Record does not exist or has been deleted.\n(Record: %s(%d,), User: 2)", model, id))
+		}
+	}
+	for _, id := range ids {
+		record := s.records[model][id]
+		for _, field := range slices.Sorted(maps.Keys(values)) {
+			value := values[field]
+			if field == "id" {
+				continue
+			}
+			if relation, isOne2Many := s.one2many[model][field]; isOne2Many {
+				if err := s.applyOne2Many(id, relation, value); err != nil {
+					return false, fmt.Errorf("field %s: %w", field, err)
+				}
+				continue
+			}
+			if commands, isCommands := toCommands(value); isCommands {
+				current, _ := record[field].([]any)
+				updated, err := applyMany2Many(current, commands)
+				if err != nil {
+					return false, fmt.Errorf("field %s: %w", field, err)
+				}
+				record[field] = updated
+				continue
+			}
+			record[field] = value
+		}
+	}
+	return true, nil
+}
+
+func (s *Server) applyOne2Many(id int, relation one2Many, value any) error {
+	commands, ok := toCommands(value)
+	if !ok {
+		return fmt.Errorf("expected a list of commands, got %v", value)
+	}
+	for _, command := range commands {
+		code, _ := command[0].(float64)
+		lineID := toID(command[1])
+		switch code {
+		case 0:
+			values, _ := command[2].(map[string]any)
+			values = maps.Clone(values)
+			values[relation.inverse] = float64(id)
+			if _, err := s.create(relation.comodel, values); err != nil {
+				return err
+			}
+		case 1:
+			values, _ := command[2].(map[string]any)
+			if _, err := s.write(relation.comodel, []int{lineID}, values); err != nil {
+				return err
+			}
+		case 2:
+			if _, err := s.unlink(relation.comodel, []int{lineID}); err != nil {
+				return err
+			}
+		case 3:
+			if line, found := s.records[relation.comodel][lineID]; found {
+				line[relation.inverse] = false
+			}
+		case 4:
+			if _, err := s.write(relation.comodel, []int{lineID}, map[string]any{relation.inverse: float64(id)}); err != nil {
+				return err
+			}
+		case 5, 6:
+			keep := []int{}
+			if code == 6 {
+				keep = toIDs(command[2])
+			}
+			for lineID, line := range s.records[relation.comodel] {
+				if toID(line[relation.inverse]) == id && !slices.Contains(keep, lineID) {
+					line[relation.inverse] = false
+				}
+			}
+			for _, lineID := range keep {
+				if _, err := s.write(relation.comodel, []int{lineID}, map[string]any{relation.inverse: float64(id)}); err != nil {
+					return err
+				}
+			}
+		default:
+			return fmt.Errorf("unknown command %v", command)
+		}
+	}
+	return nil
+}
+
+func applyMany2Many(current []any, commands [][]any) ([]any, error) {
+	ids := toIDs(current)
+	for _, command := range commands {
+		code, _ := command[0].(float64)
+		switch code {
+		case 2, 3:
+			ids = slices.DeleteFunc(ids, func(id int) bool { return id == toID(command[1]) })
+		case 4:
+			if !slices.Contains(ids, toID(command[1])) {
+				ids = append(ids, toID(command[1]))
+			}
+		case 5:
+			ids = []int{}
+		case 6:
+			ids = toIDs(command[2])
+		default:
+			return nil, fmt.Errorf("command %v is not supported on many2many fields by odootest", command)
+		}
+	}
+	result := make([]any, len(ids))
+	for i, id := range ids {
+		result[i] = float64(id)
+	}
+	return result, nil
+}
+
+func (s *Server) unlink(model string, ids []int) (bool, error) {
+	for _, id := range ids {
+		if _, found := s.records[model][id]; !found {
+			return false, Exception(odoo.ExceptionMissing, fmt.Sprintf("Record does not exist or has been deleted.\n(Record: %s(%d,), User: 2)", model, id))
+		}
+	}
+	for _, id := range ids {
+		delete(s.records[model], id)
+		for dataID, data := range s.records["ir.model.data"] {
+			if data["model"] == model && toID(data["res_id"]) == id {
+				delete(s.records["ir.model.data"], dataID)
+			}
+		}
+		for _, relation := range s.one2many[model] {
+			for lineID, line := range s.records[relation.comodel] {
+				if toID(line[relation.inverse]) == id {
+					s.unlink(relation.comodel, []int{lineID})
+				}
+			}
+		}
+	}
+	return true, nil
+}
+
+// read returns the requested fields of record (all when empty) as Odoo would send them.
+func (s *Server) read(model string, record Record, fields []string) Record {
+	if len(fields) == 0 {
+		fields = slices.Collect(maps.Keys(record))
+		fields = append(fields, slices.Collect(maps.Keys(s.one2many[model]))...)
+	}
+	result := Record{"id": record["id"]}
+	for _, field := range fields {
+		value := s.value(model, record, field)
+		if comodel := s.relation(model, field); comodel != "" {
+			if id := toID(value); id != 0 {
+				value = []any{float64(id), s.displayName(comodel, id)}
+			}
+		}
+		if value == nil {
+			value = false
+		}
+		result[field] = value
+	}
+	return result
+}
+
+func (s *Server) value(model string, record Record, field string) any {
+	if relation, isOne2Many := s.one2many[model][field]; isOne2Many {
+		lineIDs := []any{}
+		for _, lineID := range slices.Sorted(maps.Keys(s.records[relation.comodel])) {
+			if toID(s.records[relation.comodel][lineID][relation.inverse]) == toID(record["id"]) {
+				lineIDs = append(lineIDs, float64(lineID))
+			}
+		}
+		return lineIDs
+	}
+	return record[field]
+}
+
+func (s *Server) relation(model, field string) string {
+	if comodel, found := s.relations[model][field]; found {
+		return comodel
+	}
+	return defaultRelations[field]
+}
+
+func (s *Server) displayName(model string, id int) string {
+	record := s.records[model][id]
+	if name, ok := record["display_name"].(string); ok {
+		return name
+	}
+	name, _ := record["name"].(string)
+	return name
+}
+
+func argOrKwarg(args []any, kwargs map[string]any, index int, name string) any {
+	if index < len(args) {
+		return args[index]
+	}
+	return kwargs[name]
+}
+
+// normalize converts values to what they would be after a JSON round trip.
+func normalize(values map[string]any) Record {
+	valuesJson, _ := json.Marshal(values)
+	normalized := Record{}
+	json.Unmarshal(valuesJson, &normalized)
+	return normalized
+}
+
+func toList(value any) []any {
+	list, _ := value.([]any)
+	return list
+}
+
+func toStrings(value any) []string {
+	result := []string{}
+	for _, item := range toList(value) {
+		if str, ok := item.(string); ok {
+			result = append(result, str)
+		}
+	}
+	return result
+}
+
+func toID(value any) int {
+	switch value := value.(type) {
+	case float64:
+		return int(value)
+	case int:
+		return value
+	case []any:
+		// many2one pair
+		if len(value) == 2 {
+			return toID(value[0])
+		}
+	}
+	return 0
+}
+
+func toIDs(value any) []int {
+	list, isList := value.([]any)
+	if !isList {
+		if id := toID(value); id != 0 {
+			return []int{id}
+		}
+	}
+	ids := []int{}
+	for _, item := range list {
+		ids = append(ids, toID(item))
+	}
+	return ids
+}
+
+// toCommands returns value as a list of x2many command tuples, if it is one.
+func toCommands(value any) ([][]any, bool) {
+	list, ok := value.([]any)
+	if !ok {
+		return nil, false
+	}
+	commands := make([][]any, 0, len(list))
+	for _, item := range list {
+		command, ok := item.([]any)
+		if !ok || len(command) != 3 {
+			return nil, false
+		}
+		if _, ok := command[0].(float64); !ok {
+			return nil, false
+		}
+		commands = append(commands, command)
+	}
+	return commands, true
+}
@@ -0,0 +1,122 @@
+package odootest
+
+import (
+	"errors"
+	"qf/go/odoo"
+	"testing"
+)
+
+func TestServer_CRUD(t *testing.T) {
+	s := NewServer(t)
+	s.SeedCountries()
+	client := s.Client(t)
+
+	countryId, stateId := client.GetCountryAndStateIds("CA", "QC")
+	if countryId == 0 || stateId == 0 {
+		t.Fatalf("expected seeded country and state, got %v, %v", countryId, stateId)
+	}
+
+	id, err := client.Create("res.partner", map[string]any{"name": "Test", "country_id": countryId}, map[string]any{"xid": "__export__.test_partner"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if found, err := client.GetIDByXID("res.partner", "__export__.test_partner"); err != nil || found != id {
+		t.Fatalf("expected XID to resolve to %v, got %v, %v", id, found, err)
+	}
+	records, err := client.SearchRead("res.partner", odoo.And(odoo.Cond("name", odoo.OpEqILike, "test"), odoo.Cond("email", odoo.OpEq, false)), []string{"country_id"}, 0, nil)
+	if err != nil || len(records) != 1 {
+		t.Fatalf("unexpected search result %v, %v", records, err)
+	}
+	if country, _ := records[0]["country_id"].([]any); len(country) != 2 || country[1] != "Canada" {
+		t.Fatalf("expected many2one pair, got %v", records[0]["country_id"])
+	}
+
+	if err := client.Write("res.partner", id, map[string]any{"active": false}, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count, _ := client.SearchCount("res.partner", []any{}, nil); count != 0 {
+		t.Fatalf("expected archived partner to be hidden, got %v", count)
+	}
+	if err := client.Unlink("res.partner", id, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if model, _ := s.XID("__export__.test_partner"); model != "" {
+		t.Fatalf("expected XID to be removed with its record")
+	}
+	if err := client.Write("res.partner", id, map[string]any{"name": "Deleted"}, nil); !odoo.IsRPCException(err, odoo.ExceptionMissing) {
+		t.Fatalf("expected MissingError, got %v", err)
+	}
+}
+
+func TestServer_XIDUnique(t *testing.T) {
+	s := NewServer(t)
+	client := s.Client(t)
+	data := map[string]any{"module": "__export__", "name": "dup", "model": "res.partner", "res_id": 1}
+	if _, err := client.Create("ir.model.data", data, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.Create("ir.model.data", data, nil); !odoo.IsRPCException(err, "UniqueViolation") {
+		t.Fatalf("expected unique violation, got %v", err)
+	}
+}
+
+func TestServer_Commands(t *testing.T) {
+	s := NewServer(t)
+	s.Seed("res.partner", Record{"name": "Customer"})
+	client := s.Client(t)
+
+	orderId, err := client.Create("sale.order", map[string]any{
+		"partner_id": 1,
+		"order_line": []any{odoo.Command.Create(map[string]any{"name": "A"}), odoo.Command.Create(map[string]any{"name": "B"})},
+	}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lineIds, _ := client.SearchIds("sale.order.line", []any{[]any{"order_id", "=", orderId}}, nil)
+	if len(lineIds) != 2 {
+		t.Fatalf("expected 2 lines, got %v", lineIds)
+	}
+	err = client.Write("sale.order", orderId, map[string]any{
+		"order_line": []any{odoo.Command.Update(lineIds[0], map[string]any{"name": "A2", "tax_id": []any{odoo.Command.Set([]int{4, 5})}}), odoo.Command.Delete(lineIds[1])},
+	}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := s.Records("sale.order.line")
+	if len(lines) != 1 || lines[0]["name"] != "A2" || len(lines[0]["tax_id"].([]any)) != 2 {
+		t.Fatalf("unexpected lines: %v", lines)
+	}
+
+	if _, err := client.JsonRpcExecuteKw("sale.order", "action_confirm", []any{[]int{orderId}}, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	order, err := client.SearchReadById("sale.order", orderId, []string{"state", "name", "commercial_partner_id"}, nil)
+	if err != nil || order["state"] != "sale" || order["name"] != "S00001" {
+		t.Fatalf("unexpected order %v, %v", order, err)
+	}
+}
+
+func TestServer_Session(t *testing.T) {
+	s := NewServer(t)
+	s.SeedCountries()
+	client, err := odoo.NewClient(odoo.Config{URL: s.URL, DB: "odootest", Login: s.Login, Password: s.Password, Auth: odoo.AuthSession})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if count, err := client.SearchCount("res.country", []any{}, nil); err != nil || count != 2 {
+		t.Fatalf("expected countries through the session, got %v, %v", count, err)
+	}
+	if uid, err := client.UserID(); err != nil || uid != UserID {
+		t.Fatalf("expected the session user, got %v, %v", uid, err)
+	}
+	s.ExpireSessions()
+	if count, err := client.SearchCount("res.country", []any{}, nil); err != nil || count != 2 {
+		t.Fatalf("expected the session to be renewed, got %v, %v", count, err)
+	}
+
+	rejected, _ := odoo.NewClient(odoo.Config{URL: s.URL, DB: "odootest", Login: s.Login, Password: "wrong", Auth: odoo.AuthSession})
+	if _, err := rejected.SearchCount("res.country", []any{}, nil); !errors.Is(err, odoo.ErrAuthentication) {
+		t.Fatalf("expected authentication error, got %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package odoo
+
+import (
+	"fmt"
+	"strings"
+)
+
+type ReadGroupOptions struct {
+	Lazy    bool   // Group by the first groupby only, as the Odoo UI does. Defaults to grouping by all of them
+	OrderBy string // e.g. "date_order:day desc"
+	Offset  int
+	Limit   int
+	Context map[string]any
+}
+
+// Group is one row of a read_group result.
+type Group struct {
+	Keys   map[string]any // Value of each groupby, e.g. "company_id": [2, "QF"], "date_order:day": "01 Jun 2025"
+	Count  int            // Records in the group
+	Values map[string]any // Aggregated fields
+	Domain []any          // Domain matching the records in the group
+	Range  map[string]any // Bounds of date/datetime groups, when returned by the server
+}
+
+// Key returns the value of the group for groupby, as passed to ReadGroup.
+func (g Group) Key(groupby string) any {
+	return g.Keys[groupby]
+}
+
+// KeyID returns the record ID of a many2one groupby, or 0 for the group without value.
+func (g Group) KeyID(groupby string) int {
+	pair, ok := g.Keys[groupby].([]any)
+	if !ok || len(pair) == 0 {
+		return 0
+	}
+	id, _ := pair[0].(float64)
+	return int(id)
+}
+
+// Sum returns the aggregated value of field, or 0 if it was not aggregated.
+func (g Group) Sum(field string) float64 {
+	value, _ := g.Values[field].(float64)
+	return value
+}
+
+// ReadGroup runs read_group on model. Fields use Odoo's aggregate syntax, e.g.
+// "amount_total:sum", and groupby accepts date granularities such as "date_order:day".
+func (c *Client) ReadGroup(model string, domain []any, fields []string, groupby []string, options ReadGroupOptions) ([]Group, error) {
+	if len(groupby) == 0 {
+		return nil, fmt.Errorf("read_group requires at least one groupby")
+	}
+	kwargs := map[string]any{
+		"domain":  domain,
+		"fields":  fields,
+		"groupby": groupby,
+		"lazy":    options.Lazy,
+		"context": options.Context,
+	}
+	if options.Offset != 0 {
+		kwargs["offset"] = options.Offset
+	}
+	if options.Limit != 0 {
+		kwargs["limit"] = options.Limit
+	}
+	if options.OrderBy != "" {
+		kwargs["orderby"] = options.OrderBy
+	}
+	result, err := c.JsonRpcExecuteKw(model, "read_group", []any{}, kwargs)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, ok := result.([]any)
+	if !ok {
+		return nil, fmt.Errorf("read_group result is not valid")
+	}
+
+	countKey := "__count"
+	groupKeys := groupby
+	if options.Lazy {
+		countKey = strings.Split(groupby[0], ":")[0] + "_count"
+		groupKeys = groupby[:1]
+	}
+
+	groups := make([]Group, len(rows))
+	for i, row := range rows {
+		rowMap, ok := row.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("read_group result values are not valid")
+		}
+		group := Group{Keys: map[string]any{}, Values: map[string]any{}}
+		for key, value := range rowMap {
+			switch {
+			case key == countKey:
+				count, _ := value.(float64)
+				group.Count = int(count)
+			case key == "__domain":
+				group.Domain, _ = value.([]any)
+			case key == "__range":
+				group.Range, _ = value.(map[string]any)
+			case strings.HasPrefix(key, "__"):
+				// __context, __fold, __offset...
+			default:
+				group.Values[key] = value
+			}
+		}
+		for _, key := range groupKeys {
+			group.Keys[key] = rowMap[key]
+			delete(group.Values, key)
+		}
+		groups[i] = group
+	}
+
+	return groups, nil
+}
@@ -0,0 +1,63 @@
+package odoo
+
+import (
+	"testing"
+)
+
+func TestReadGroup(t *testing.T) {
+	requests := []rpcRequest{}
+	client := newTestClient(t, &requests, func(rpcRequest) (int, any) {
+		return resultResponse([]any{
+			map[string]any{
+				"company_id":     []any{2.0, "QF"},
+				"date_order:day": "01 Jun 2025",
+				"amount_total":   150.5,
+				"__count":        3.0,
+				"__domain":       []any{[]any{"company_id", "=", 2.0}},
+				"__range":        map[string]any{"date_order:day": map[string]any{"from": "2025-06-01", "to": "2025-06-02"}},
+			},
+			map[string]any{
+				"company_id":     false,
+				"date_order:day": "01 Jun 2025",
+				"amount_total":   10.0,
+				"__count":        1.0,
+			},
+		})
+	})
+
+	groups, err := client.ReadGroup("sale.order", []any{}, []string{"amount_total:sum"}, []string{"company_id", "date_order:day"}, ReadGroupOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 groups, got %v", groups)
+	}
+	if groups[0].KeyID("company_id") != 2 || groups[0].Key("date_order:day") != "01 Jun 2025" || groups[0].Count != 3 || groups[0].Sum("amount_total") != 150.5 {
+		t.Fatalf("unexpected first group: %+v", groups[0])
+	}
+	if len(groups[0].Values) != 1 || len(groups[0].Domain) != 1 || groups[0].Range == nil {
+		t.Fatalf("unexpected first group values: %+v", groups[0])
+	}
+	if groups[1].KeyID("company_id") != 0 || groups[1].Count != 1 {
+		t.Fatalf("unexpected second group: %+v", groups[1])
+	}
+	kwargs := requests[0].Params["args"].([]any)[6].(map[string]any)
+	if kwargs["lazy"] != false {
+		t.Fatalf("expected non-lazy grouping by default, got %v", kwargs)
+	}
+}
+
+func TestReadGroup_Lazy(t *testing.T) {
+	client := newTestClient(t, nil, func(rpcRequest) (int, any) {
+		return resultResponse([]any{
+			map[string]any{"company_id": []any{3.0, "FM"}, "company_id_count": 4.0, "amount_total": 20.0},
+		})
+	})
+	groups, err := client.ReadGroup("sale.order", []any{}, []string{"amount_total:sum"}, []string{"company_id", "date_order:day"}, ReadGroupOptions{Lazy: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(groups) != 1 || groups[0].Count != 4 || groups[0].KeyID("company_id") != 3 || len(groups[0].Keys) != 1 {
+		t.Fatalf("unexpected groups: %+v", groups)
+	}
+}
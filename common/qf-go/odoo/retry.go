@@ -0,0 +1,157 @@
+package odoo
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// RetryPolicy controls how failed JSON-RPC calls are retried. Reads are retried on any
+// transport or gateway error, and idempotent writes (write) on 502 and 503 from the proxy,
+// which may come after the transaction committed. Other writes, such as create or unlink,
+// are only retried when Odoo rolled back (serialization failures, deadlocks).
+type RetryPolicy struct {
+	MaxAttempts int           // Total attempts including the first one, 1 disables retries
+	BaseDelay   time.Duration // Delay before the first retry, doubled on every attempt
+	MaxDelay    time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   250 * time.Millisecond,
+	MaxDelay:    4 * time.Second,
+}
+
+// Methods that never change data, safe to retry on any transient failure.
+var readMethods = map[string]bool{
+	"search":              true,
+	"search_read":         true,
+	"search_count":        true,
+	"read":                true,
+	"read_group":          true,
+	"fields_get":          true,
+	"name_get":            true,
+	"name_search":         true,
+	"default_get":         true,
+	"check_access_rights": true,
+}
+
+func isReadMethod(method string) bool {
+	return readMethods[method]
+}
+
+// Writes giving the same result when repeated, safe to retry when it is unknown whether
+// the first attempt committed. Not unlink, which fails on records already deleted.
+var idempotentMethods = map[string]bool{
+	"write": true,
+}
+
+// retryKind is how safely a call can be repeated.
+type retryKind int
+
+const (
+	retryWrite retryKind = iota
+	retryIdempotent
+	retryRead
+)
+
+func retryKindOf(method string) retryKind {
+	switch {
+	case isReadMethod(method):
+		return retryRead
+	case idempotentMethods[method]:
+		return retryIdempotent
+	}
+	return retryWrite
+}
+
+// HTTPError is returned when Odoo (or its proxy) answers with a non-200 status.
+type HTTPError struct {
+	StatusCode int
+	Status     string
+	Body       string
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("non-200 response from Odoo JSON-RPC call: [%s] %s", e.Status, e.Body)
+}
+
+// RetryError wraps the last error of a call that was attempted more than once.
+type RetryError struct {
+	Attempts int
+	Err      error
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("%v\n>>> failed after %d attempts", e.Err, e.Attempts)
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+var transientServerMessages = []string{
+	"could not serialize access due to concurrent update",
+	"deadlock detected",
+	"SerializationFailure",
+	"TransactionRollbackError",
+}
+
+func isTransient(err error, kind retryKind) bool {
+	if errors.Is(err, ErrAuthentication) {
+		return false
+	}
+	var rpcError *RPCError
+	if errors.As(err, &rpcError) {
+		for _, msg := range transientServerMessages {
+			if strings.Contains(rpcError.Name, msg) || strings.Contains(rpcError.Detail, msg) || strings.Contains(rpcError.Debug, msg) {
+				return true
+			}
+		}
+		return false
+	}
+	var httpError *HTTPError
+	if errors.As(err, &httpError) {
+		switch httpError.StatusCode {
+		case http.StatusBadGateway, http.StatusServiceUnavailable:
+			return kind >= retryIdempotent
+		case http.StatusGatewayTimeout, http.StatusTooManyRequests:
+			return kind == retryRead
+		}
+		return false
+	}
+	// Request or response errors, the call may or may not have reached Odoo
+	return kind == retryRead
+}
+
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	delay := p.BaseDelay << (attempt - 1)
+	if p.MaxDelay > 0 && (delay > p.MaxDelay || delay <= 0) {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	// Equal jitter: half fixed, half random, so concurrent webhooks spread out
+	return delay/2 + rand.N(delay/2+1)
+}
+
+func (c *Client) withRetry(kind retryKind, call func() (any, error)) (any, error) {
+	attempts := max(c.config.Retry.MaxAttempts, 1)
+	for attempt := 1; ; attempt++ {
+		result, err := call()
+		if err == nil {
+			return result, nil
+		}
+		if attempt >= attempts || !isTransient(err, kind) {
+			if attempt > 1 {
+				return nil, &RetryError{Attempts: attempt, Err: err}
+			}
+			return nil, err
+		}
+		c.sleep(c.config.Retry.delay(attempt))
+	}
+}
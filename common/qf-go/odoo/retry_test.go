@@ -0,0 +1,86 @@
+package odoo
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestClient_Retry(t *testing.T) {
+	serializationError := map[string]any{"jsonrpc": "2.0", "id": 1, "error": map[string]any{
+		"code":    200.0,
+		"message": "Odoo Server Error",
+		"data": map[string]any{
+			"name":    "psycopg2.errors.SerializationFailure",
+			"message": "could not serialize access due to concurrent update",
+		},
+	}}
+	tests := []struct {
+		Title            string
+		Method           string
+		Failures         []any // Status code (int) or JSON-RPC error response (map)
+		ExpectedAttempts int
+		ExpectError      bool
+	}{
+		{Title: "Read retried on 502", Method: "search_read", Failures: []any{502, 502}, ExpectedAttempts: 3},
+		{Title: "Read retried on 504", Method: "search_read", Failures: []any{504}, ExpectedAttempts: 2},
+		{Title: "Read gives up after max attempts", Method: "search_read", Failures: []any{502, 502, 502, 502}, ExpectedAttempts: 3, ExpectError: true},
+		{Title: "Write retried on 503", Method: "write", Failures: []any{503}, ExpectedAttempts: 2},
+		{Title: "Unlink not retried on 502", Method: "unlink", Failures: []any{502}, ExpectedAttempts: 1, ExpectError: true},
+		{Title: "Create not retried on 502", Method: "create", Failures: []any{502}, ExpectedAttempts: 1, ExpectError: true},
+		{Title: "Method not retried on 503", Method: "message_post", Failures: []any{503}, ExpectedAttempts: 1, ExpectError: true},
+		{Title: "Write not retried on 504", Method: "write", Failures: []any{504}, ExpectedAttempts: 1, ExpectError: true},
+		{Title: "Write retried on serialization failure", Method: "write", Failures: []any{serializationError}, ExpectedAttempts: 2},
+		{Title: "Non-transient error not retried", Method: "search_read", Failures: []any{500}, ExpectedAttempts: 1, ExpectError: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.Title, func(t *testing.T) {
+			requests := []rpcRequest{}
+			client := newTestClient(t, &requests, func(rpcRequest) (int, any) {
+				if len(requests) <= len(tc.Failures) {
+					failure := tc.Failures[len(requests)-1]
+					if status, ok := failure.(int); ok {
+						return status, "Bad Gateway"
+					}
+					return 200, failure
+				}
+				return resultResponse(true)
+			})
+			_, err := client.JsonRpcExecuteKw("sale.order", tc.Method, []any{}, nil)
+			if len(requests) != tc.ExpectedAttempts {
+				t.Fatalf("expected %d attempts, got %d", tc.ExpectedAttempts, len(requests))
+			}
+			if !tc.ExpectError {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected error")
+			}
+			var retryError *RetryError
+			if tc.ExpectedAttempts > 1 {
+				if !errors.As(err, &retryError) || retryError.Attempts != tc.ExpectedAttempts || !strings.Contains(err.Error(), "failed after") {
+					t.Fatalf("expected attempts to be surfaced in the error, got %v", err)
+				}
+			} else if errors.As(err, &retryError) {
+				t.Fatalf("unexpected retry error for a single attempt: %v", err)
+			}
+			var httpError *HTTPError
+			if !errors.As(err, &httpError) {
+				t.Fatalf("expected HTTPError to be wrapped, got %v", err)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, BaseDelay: 100, MaxDelay: 300}
+	for attempt, maxDelay := range map[int]int{1: 100, 2: 200, 3: 300, 4: 300, 40: 300} {
+		delay := policy.delay(attempt)
+		if int(delay) < maxDelay/2 || int(delay) > maxDelay {
+			t.Fatalf("delay for attempt %d out of range: %v", attempt, delay)
+		}
+	}
+}
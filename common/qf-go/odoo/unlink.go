@@ -0,0 +1,78 @@
+package odoo
+
+import (
+	"fmt"
+	"maps"
+)
+
+// UnlinkPolicy replaces the deletion of records by a method call, a write, or both, so
+// records already seen by users are kept, e.g. cancelled or archived.
+type UnlinkPolicy struct {
+	Method string         // Called first, e.g. action_cancel
+	Values map[string]any // Written next, e.g. active=false
+}
+
+var (
+	ArchivePolicy = UnlinkPolicy{Values: map[string]any{"active": false}}
+	CancelPolicy  = UnlinkPolicy{Method: "action_cancel"}
+)
+
+// WithUnlinkPolicy returns a client soft deleting the records of model with policy
+// instead of unlinking them.
+func (c *Client) WithUnlinkPolicy(model string, policy UnlinkPolicy) *Client {
+	client := *c
+	client.unlinkPolicies = maps.Clone(c.unlinkPolicies)
+	if client.unlinkPolicies == nil {
+		client.unlinkPolicies = map[string]UnlinkPolicy{}
+	}
+	client.unlinkPolicies[model] = policy
+	return &client
+}
+
+// UnlinkPolicy returns the policy set for model, if any.
+func (c *Client) UnlinkPolicy(model string) (UnlinkPolicy, bool) {
+	policy, found := c.unlinkPolicies[model]
+	return policy, found
+}
+
+// SoftDeleteMulti applies the unlink policy of model to the records, archiving them
+// when the model has none. A policy method opening a wizard returns a *WizardError.
+func (c *Client) SoftDeleteMulti(model string, ids []int, context map[string]any) error {
+	policy, found := c.UnlinkPolicy(model)
+	if !found {
+		policy = ArchivePolicy
+	}
+	if policy.Method != "" {
+		if _, err := Call[bool](c, model, policy.Method, ids, nil, map[string]any{"context": context}); err != nil {
+			return fmt.Errorf("error calling %v on %v%v instead of unlink:\n>>> %w", policy.Method, model, ids, err)
+		}
+	}
+	if len(policy.Values) != 0 {
+		if err := c.WriteMulti(model, ids, policy.Values, context); err != nil {
+			return fmt.Errorf("error writing %v on %v%v instead of unlink:\n>>> %w", policy.Values, model, ids, err)
+		}
+	}
+	return nil
+}
+
+func (c *Client) SoftDelete(model string, id int, context map[string]any) error {
+	return c.SoftDeleteMulti(model, []int{id}, context)
+}
+
+// SoftenCommands replaces the Command.Delete of x2many commands on comodel by updates
+// with the values of its unlink policy. Policy methods cannot be applied by commands,
+// so a policy without values leaves the commands unchanged.
+func (c *Client) SoftenCommands(comodel string, commands []any) []any {
+	policy, found := c.UnlinkPolicy(comodel)
+	if !found || len(policy.Values) == 0 {
+		return commands
+	}
+	softened := make([]any, len(commands))
+	for i, command := range commands {
+		softened[i] = command
+		if command, isCommand := command.([]any); isCommand && len(command) == 3 && command[0] == 2 {
+			softened[i] = Command.Update(recordId(command[1]), maps.Clone(policy.Values))
+		}
+	}
+	return softened
+}
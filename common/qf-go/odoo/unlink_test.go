@@ -0,0 +1,72 @@
+package odoo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnlinkPolicy(t *testing.T) {
+	requests := []rpcRequest{}
+	client := newTestClient(t, &requests, func(request rpcRequest) (int, any) {
+		if request.Params["args"].([]any)[4] == "action_cancel" {
+			return resultResponse(nil)
+		}
+		return resultResponse(true)
+	})
+	methods := func() []string {
+		called := []string{}
+		for _, request := range requests {
+			args := request.Params["args"].([]any)
+			called = append(called, args[3].(string)+"."+args[4].(string))
+		}
+		requests = requests[:0]
+		return called
+	}
+
+	if err := client.Unlink("sale.order", 1, nil); err != nil {
+		t.Fatal(err)
+	}
+	if called := methods(); !reflect.DeepEqual(called, []string{"sale.order.unlink"}) {
+		t.Fatalf("expected a hard delete without policy, got %v", called)
+	}
+
+	oc := client.WithUnlinkPolicy("sale.order", CancelPolicy).WithUnlinkPolicy("res.partner", ArchivePolicy)
+	if _, found := client.UnlinkPolicy("sale.order"); found {
+		t.Fatalf("expected the original client to be unaffected")
+	}
+	if err := oc.Unlink("sale.order", 1, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := oc.UnlinkMulti("res.partner", []int{2, 3}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if values := requests[1].Params["args"].([]any)[5].([]any)[1]; !reflect.DeepEqual(values, map[string]any{"active": false}) {
+		t.Fatalf("expected partners to be archived, got %v", values)
+	}
+	if called := methods(); !reflect.DeepEqual(called, []string{"sale.order.action_cancel", "res.partner.write"}) {
+		t.Fatalf("expected the policies to be applied, got %v", called)
+	}
+
+	if err := client.SoftDelete("product.product", 4, nil); err != nil {
+		t.Fatal(err)
+	}
+	if called := methods(); !reflect.DeepEqual(called, []string{"product.product.write"}) {
+		t.Fatalf("expected SoftDelete to archive without policy, got %v", called)
+	}
+}
+
+func TestSoftenCommands(t *testing.T) {
+	commands := []any{Command.Update(1, map[string]any{"name": "A"}), Command.Delete(2)}
+	client := &Client{}
+	if softened := client.SoftenCommands("sale.order.line", commands); !reflect.DeepEqual(softened, commands) {
+		t.Fatalf("expected commands unchanged without policy, got %v", softened)
+	}
+	if softened := client.WithUnlinkPolicy("sale.order.line", CancelPolicy).SoftenCommands("sale.order.line", commands); !reflect.DeepEqual(softened, commands) {
+		t.Fatalf("expected commands unchanged with a method policy, got %v", softened)
+	}
+	lc := client.WithUnlinkPolicy("sale.order.line", UnlinkPolicy{Values: map[string]any{"product_uom_qty": 0}})
+	expected := []any{commands[0], Command.Update(2, map[string]any{"product_uom_qty": 0})}
+	if softened := lc.SoftenCommands("sale.order.line", commands); !reflect.DeepEqual(softened, expected) {
+		t.Fatalf("expected the delete to become an update, got %v", softened)
+	}
+}
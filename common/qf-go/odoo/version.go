@@ -0,0 +1,160 @@
+package odoo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Version is the Odoo server version, e.g. 16.0.
+type Version struct {
+	Major int
+	Minor int
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+}
+
+// ParseVersion parses a server serie such as "16.0" or "saas~17.2".
+func ParseVersion(serie string) (Version, error) {
+	serie = strings.TrimPrefix(serie, "saas~")
+	majorStr, minorStr, _ := strings.Cut(serie, ".")
+	major, err := strconv.Atoi(majorStr)
+	if err != nil {
+		return Version{}, fmt.Errorf("invalid Odoo version %q", serie)
+	}
+	minor := 0
+	if minorStr != "" {
+		minor, err = strconv.Atoi(strings.TrimRightFunc(minorStr, func(r rune) bool { return r < '0' || r > '9' }))
+		if err != nil {
+			return Version{}, fmt.Errorf("invalid Odoo version %q", serie)
+		}
+	}
+	return Version{Major: major, Minor: minor}, nil
+}
+
+// Alias renames a model starting from server version Since.
+type Alias struct {
+	Since int
+	Name  string
+}
+
+// ModelAliases and FieldAliases map the names used in our code (Odoo 15) to the names
+// of later versions, so the same code can run against both sides of an upgrade. Fields
+// are resolved from FieldsGet rather than versions, an empty name means the field was
+// removed.
+var (
+	ModelAliases = map[string]Alias{
+		"payment.acquirer": {Since: 16, Name: "payment.provider"},
+	}
+	FieldAliases = map[string]map[string]string{
+		"payment.transaction": {
+			"acquirer_id":        "provider_id",
+			"acquirer_reference": "provider_reference",
+		},
+		"sale.order.line": {
+			"tax_id": "tax_ids",
+		},
+		"res.partner": {
+			"mobile": "",
+		},
+	}
+)
+
+// Versions are cached per process for each Odoo server, detected on first use.
+var versionCache = struct {
+	sync.Mutex
+	versions map[string]Version
+}{versions: map[string]Version{}}
+
+// ServerVersion returns the version of the Odoo server, from Config.Version when set
+// or else detected once through common.version.
+func (c *Client) ServerVersion() (Version, error) {
+	if c.config.Version != "" {
+		return ParseVersion(c.config.Version)
+	}
+	versionCache.Lock()
+	version, found := versionCache.versions[c.config.URL]
+	versionCache.Unlock()
+	if found {
+		return version, nil
+	}
+
+	result, err := c.withRetry(retryRead, func() (any, error) {
+		return c.jsonRpc("common", "version", []any{})
+	})
+	if err != nil {
+		return Version{}, fmt.Errorf("error getting Odoo server version\nERROR=%w", err)
+	}
+	resultMap, _ := result.(map[string]any)
+	serie, _ := resultMap["server_serie"].(string)
+	version, err = ParseVersion(serie)
+	if err != nil {
+		return Version{}, err
+	}
+
+	versionCache.Lock()
+	versionCache.versions[c.config.URL] = version
+	versionCache.Unlock()
+	return version, nil
+}
+
+// ModelName returns the name of model on the server version.
+func (c *Client) ModelName(model string) (string, error) {
+	alias, found := ModelAliases[model]
+	if !found {
+		return model, nil
+	}
+	version, err := c.ServerVersion()
+	if err != nil {
+		return "", err
+	}
+	if version.Major >= alias.Since {
+		return alias.Name, nil
+	}
+	return model, nil
+}
+
+// FieldName returns the name of field of model on the server, its alias when the server
+// only has that one, or "" if the field was removed.
+func (c *Client) FieldName(model string, field string) (string, error) {
+	alias, found := FieldAliases[model][field]
+	if !found {
+		return field, nil
+	}
+	fields, err := c.FieldsGet(model)
+	if err != nil {
+		return "", fmt.Errorf("error resolving field %v of %v\nERROR=%w", field, model, err)
+	}
+	if _, exists := fields[field]; exists {
+		return field, nil
+	}
+	if alias == "" {
+		return "", nil
+	}
+	if _, exists := fields[alias]; exists {
+		return alias, nil
+	}
+	return "", fmt.Errorf("neither field %v nor %v found in %v", field, alias, model)
+}
+
+// CompatValues returns a copy of values for model with the fields renamed for the
+// server, dropping the fields it no longer has.
+func (c *Client) CompatValues(model string, values map[string]any) (map[string]any, error) {
+	if len(FieldAliases[model]) == 0 {
+		return values, nil
+	}
+	compat := make(map[string]any, len(values))
+	for field, value := range values {
+		name, err := c.FieldName(model, field)
+		if err != nil {
+			return nil, err
+		}
+		if name != "" {
+			compat[name] = value
+		}
+	}
+	return compat, nil
+}
@@ -0,0 +1,95 @@
+package odoo
+
+import (
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	cases := map[string]Version{
+		"15.0":      {15, 0},
+		"16.0":      {16, 0},
+		"saas~17.2": {17, 2},
+		"17.0+e":    {17, 0},
+	}
+	for serie, expected := range cases {
+		version, err := ParseVersion(serie)
+		if err != nil || version != expected {
+			t.Errorf("ParseVersion(%q) = %v, %v; expected %v", serie, version, err, expected)
+		}
+	}
+	if _, err := ParseVersion("master"); err == nil {
+		t.Errorf("expected error for invalid version")
+	}
+}
+
+func TestServerVersion_Detected(t *testing.T) {
+	requests := []rpcRequest{}
+	client := newTestClient(t, &requests, func(rpcRequest) (int, any) {
+		return resultResponse(map[string]any{"server_serie": "16.0", "server_version_info": []any{16.0, 0.0, 0.0, "final", 0.0, ""}})
+	})
+	client.config.URL = "https://version.odoo.test"
+
+	for range 2 {
+		model, err := client.ModelName("payment.acquirer")
+		if err != nil || model != "payment.provider" {
+			t.Fatalf("expected payment.provider, got %v, %v", model, err)
+		}
+	}
+	if len(requests) != 1 || requests[0].Params["service"] != "common" || requests[0].Params["method"] != "version" {
+		t.Fatalf("expected version to be detected once, got %v", requests)
+	}
+}
+
+func TestCompatValues_FieldsGet(t *testing.T) {
+	ResetFieldsCache()
+	defer ResetFieldsCache()
+	upgradedFields := map[string]map[string]any{
+		"payment.transaction": {"provider_id": map[string]any{"type": "many2one"}, "provider_reference": map[string]any{"type": "char"}, "amount": map[string]any{"type": "monetary"}},
+		"res.partner":         {"name": map[string]any{"type": "char"}},
+		"sale.order.line":     {"tax_ids": map[string]any{"type": "many2many"}},
+	}
+	requests := []rpcRequest{}
+	client := newTestClient(t, &requests, func(request rpcRequest) (int, any) {
+		args := request.Params["args"].([]any)
+		return resultResponse(upgradedFields[args[3].(string)])
+	})
+
+	values, err := client.CompatValues("payment.transaction", map[string]any{"acquirer_id": 1, "acquirer_reference": "123", "amount": 10.0})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if values["provider_id"] != 1 || values["provider_reference"] != "123" || values["amount"] != 10.0 || len(values) != 3 {
+		t.Fatalf("unexpected values: %v", values)
+	}
+	values, _ = client.CompatValues("res.partner", map[string]any{"name": "Test", "mobile": "555"})
+	if _, found := values["mobile"]; found || values["name"] != "Test" {
+		t.Fatalf("expected mobile to be dropped, got %v", values)
+	}
+	if field, _ := client.FieldName("sale.order.line", "tax_id"); field != "tax_ids" {
+		t.Fatalf("expected tax_ids, got %v", field)
+	}
+	for _, request := range requests {
+		if request.Params["service"] != "object" || request.Params["args"].([]any)[4] != "fields_get" {
+			t.Fatalf("expected fields to be resolved with fields_get only, got %v", request.Params)
+		}
+	}
+}
+
+func TestCompatValues_FieldsKept(t *testing.T) {
+	ResetFieldsCache()
+	defer ResetFieldsCache()
+	client := newTestClient(t, nil, func(rpcRequest) (int, any) {
+		return resultResponse(map[string]any{"name": map[string]any{"type": "char"}, "mobile": map[string]any{"type": "char"}, "tax_id": map[string]any{"type": "many2many"}})
+	})
+
+	values, _ := client.CompatValues("res.partner", map[string]any{"name": "Test", "mobile": "555"})
+	if values["mobile"] != "555" {
+		t.Fatalf("expected mobile to be kept, got %v", values)
+	}
+	if field, _ := client.FieldName("sale.order.line", "tax_id"); field != "tax_id" {
+		t.Fatalf("expected tax_id to be kept, got %v", field)
+	}
+	if _, err := client.FieldName("payment.transaction", "acquirer_id"); err == nil {
+		t.Fatalf("expected error when neither the field nor its alias exist")
+	}
+}
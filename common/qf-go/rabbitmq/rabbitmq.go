@@ -10,7 +10,7 @@ import (
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
-func PublishMessage(exchange, routingKey, body string) error {
+func PublishMessage(exchange, routingKey, body string, headers map[string]any) error {
 	rHost := os.Getenv("RABBITMQ_HOST")
 	rUser := os.Getenv("RABBITMQ_USER")
 	rPass := os.Getenv("RABBITMQ_PASSWORD")
@@ -36,6 +36,7 @@ func PublishMessage(exchange, routingKey, body string) error {
 
 	err = ch.PublishWithContext(ctx, exchange, routingKey, false, false, amqp.Publishing{
 		ContentType:  "text/plain",
+		Headers:      amqp.Table(headers),
 		Body:         []byte(body),
 		DeliveryMode: amqp.Persistent,
 	})
@@ -218,6 +218,10 @@ fragment OrderTransactionFields on OrderTransaction {
 var orderWithTransactionsFragment = orderTransactionFragment + `
 fragment OrderWithTransactionsFields on Order {
 	id
+	customAttributes {
+		key
+		value
+	}
 	transactions {
 		...OrderTransactionFields
 		parentTransaction {
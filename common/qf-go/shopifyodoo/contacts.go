@@ -3,14 +3,18 @@ package shopifyodoo
 import (
 	"fmt"
 	"maps"
-	"qf/go/helpers"
 	"qf/go/odoo"
 	"qf/go/shopify/adminapi"
 	"qf/go/shopify/adminapi/types"
 	"strings"
 )
 
-func mapShopifyAddressToOdoo(address *types.Address, extra map[string]any) map[string]any {
+type odooTeam struct {
+	ID   int           `odoo:"id"`
+	User odoo.Many2One `odoo:"user_id"`
+}
+
+func mapShopifyAddressToOdoo(client *odoo.Client, address *types.Address, extra map[string]any) (map[string]any, error) {
 	addressMap := map[string]any{}
 	if address != nil && address.Id != nil {
 		maps.Copy(addressMap, map[string]any{
@@ -22,7 +26,10 @@ func mapShopifyAddressToOdoo(address *types.Address, extra map[string]any) map[s
 			"phone":   address.Phone,
 			"mobile":  address.Phone,
 		})
-		countryId, stateId := odoo.GetCountryAndStateIds(address.CountryCode(), address.ProvinceCode())
+		countryId, stateId, err := referenceClient(client).ResolveCountryState(address.CountryCode(), address.ProvinceCode())
+		if err != nil {
+			return nil, fmt.Errorf("error resolving country and province of address %v\nERROR=%w", *address.Id, err)
+		}
 		if countryId != 0 {
 			addressMap["country_id"] = countryId
 		}
@@ -33,13 +40,13 @@ func mapShopifyAddressToOdoo(address *types.Address, extra map[string]any) map[s
 	if extra != nil {
 		maps.Copy(addressMap, extra)
 	}
-	return addressMap
+	return addressMap, nil
 }
 
-func mapShopifyCustomerToOdoo(customer *types.Customer, address *types.Address, extra map[string]any) map[string]any {
+func mapShopifyCustomerToOdoo(client *odoo.Client, customer *types.Customer, address *types.Address, extra map[string]any) (map[string]any, error) {
 	splitId := strings.Split(*customer.Id, "/")
 	ref := "SHCU" + splitId[len(splitId)-1]
-	customerData := mapShopifyAddressToOdoo(address, map[string]any{
+	customerData, err := mapShopifyAddressToOdoo(client, address, map[string]any{
 		"ref":         ref,
 		"name":        customer.DisplayName,
 		"phone":       customer.DefaultPhoneNumber.PhoneNumber,
@@ -50,30 +57,42 @@ func mapShopifyCustomerToOdoo(customer *types.Customer, address *types.Address,
 		"is_customer": true,
 		"company_id":  false,
 	})
+	if err != nil {
+		return nil, err
+	}
 	if extra != nil {
 		maps.Copy(customerData, extra)
 	}
-	return customerData
+	return customerData, nil
 }
 
-func ShopifyCustomerToOdoo(shopifyId string) (odooId int, isNew bool, err error) {
+// ShopifyCustomerToOdoo syncs a customer, topic is the webhook topic mentioned in the
+// trace, if any.
+func ShopifyCustomerToOdoo(shopifyId, topic string) (odooId int, isNew bool, err error) {
+	client, stats, err := startSync()
+	subject := "customer " + shopifyId
+	defer func() { stats.done(subject, err) }()
+	if err != nil {
+		return 0, false, err
+	}
 	customer, err := adminapi.CustomerById(shopifyId)
 	if err != nil {
 		return 0, false, fmt.Errorf("shopify Admin API error while getting customer information\nERROR: %w", err)
 	}
+	subject = "customer " + customer.DisplayName
 
 	if len(customer.CompanyContacts) > 0 {
-		return shopifyCompanyContactToOdoo(customer)
+		return shopifyCompanyContactToOdoo(client, customer, topic)
 	}
-	return shopifyIndividualToOdoo(customer)
+	return shopifyIndividualToOdoo(client, customer, topic)
 }
 
-func shopifyCompanyContactToOdoo(customer *types.Customer) (odooId int, isNew bool, err error) {
+func shopifyCompanyContactToOdoo(client *odoo.Client, customer *types.Customer, topic string) (odooId int, isNew bool, err error) {
 	contactDetails := customer.CompanyContacts[0]
 	pCompanyId := contactDetails.Company.Id
 
-	companyXid, _ := ShopifyIdToOdooXid(*pCompanyId)
-	odooCompany, err := odoo.ReadRecordByXID("res.partner", companyXid, []string{"id"})
+	companyXid, _ := storeXid(*pCompanyId, webhookStoreKey)
+	odooCompany, err := client.ReadRecordByXID("res.partner", companyXid, []string{"id"})
 	if err != nil {
 		return 0, false, fmt.Errorf("error getting company from Odoo (XID=%s)\nERROR=%w", companyXid, err)
 	}
@@ -105,7 +124,8 @@ func shopifyCompanyContactToOdoo(customer *types.Customer) (odooId int, isNew bo
 		"type":      "contact",
 		"function":  contactDetails.Title,
 	}
-	roleMain, roleError := odoo.GetIDByXID("res.partner.role", "qfg_fields.res_partner_role_wholesale")
+	rc := referenceClient(client)
+	roleMain, roleError := rc.GetIDByXID("res.partner.role", "qfg_fields.res_partner_role_wholesale")
 	if roleError == nil && roleMain != 0 {
 		if contactDetails.IsMainContact {
 			extraData["contact_role_code_ids"] = []any{odoo.Command.Link(roleMain)}
@@ -114,81 +134,97 @@ func shopifyCompanyContactToOdoo(customer *types.Customer) (odooId int, isNew bo
 		}
 	}
 
-	return shopifyCustomerToOdoo(customer, &address, extraData, nil)
+	return shopifyCustomerToOdoo(client, customer, &address, extraData, nil, topic)
 }
 
-func shopifyIndividualToOdoo(customer *types.Customer) (odooId int, isNew bool, err error) {
+func shopifyIndividualToOdoo(client *odoo.Client, customer *types.Customer, topic string) (odooId int, isNew bool, err error) {
 	createData := func() map[string]any {
 		data := map[string]any{}
-		if ctype, err := odoo.GetIDByXID("customer.type", "qfg_customer_type.customer_type_individual_consumer"); err == nil && ctype != 0 {
+		rc := referenceClient(client)
+		if ctype, err := rc.GetIDByXID("customer.type", "qfg_customer_type.customer_type_individual_consumer"); err == nil && ctype != 0 {
 			data["customer_type_id"] = ctype
 		}
-		if ctype, err := odoo.SearchFirstId("account.payment.method", []any{[]any{"name", "=ilike", "shopify"}}, nil); err == nil && ctype != 0 {
+		if ctype, err := rc.SearchFirstId("account.payment.method", []any{[]any{"name", "=ilike", "shopify"}}, nil); err == nil && ctype != 0 {
 			data["customer_payment_method_id"] = ctype
 		}
-		if teams, err := odoo.SearchRead("crm.team", []any{[]any{"code", "=ilike", "consumer"}}, []string{"id", "user_id"}, 1, nil); err == nil && len(teams) != 0 {
-			data["team_id"] = int(helpers.Traverse(teams, []any{0, "id"}, 0.0))
-			data["user_id"] = int(helpers.Traverse(teams, []any{0, "user_id", 0}, 0.0))
+		if teams, err := odoo.SearchReadInto[odooTeam](rc, "crm.team", []any{[]any{"code", "=ilike", "consumer"}}, 1, nil); err == nil && len(teams) != 0 {
+			data["team_id"] = teams[0].ID
+			data["user_id"] = teams[0].User.ID
 		}
-		if qfw, err := odoo.GetIDByXID("website", "qfg.main_website"); err == nil && qfw != 0 {
+		if qfw, err := rc.GetIDByXID("website", "qfg.main_website"); err == nil && qfw != 0 {
 			data["website_id"] = qfw
 		}
-		if qz, err := odoo.GetIDByXID("product.pricelist", "qfg.pricelist_qualizon"); err == nil && qz != 0 {
+		if qz, err := rc.GetIDByXID("product.pricelist", "qfg.pricelist_qualizon"); err == nil && qz != 0 {
 			maps.Copy(data, map[string]any{
 				"qf_pricelist_id": qz,
 				"fm_pricelist_id": qz,
 			})
 		}
-		if src, err := odoo.FindFirstOrCreate("utm.source", []any{[]any{"name", "=ilike", "shopify"}}, map[string]any{"name": "Shopify"}, nil); err == nil {
+		if src, err := rc.FindFirstOrCreate("utm.source", []any{[]any{"name", "=ilike", "shopify"}}, map[string]any{"name": "Shopify"}, nil); err == nil {
 			data["source_id"] = src
 		}
 		return data
 	}
 
-	return shopifyCustomerToOdoo(customer, &customer.DefaultAddress, nil, createData)
+	return shopifyCustomerToOdoo(client, customer, &customer.DefaultAddress, nil, createData, topic)
 }
 
-func shopifyCustomerToOdoo(customer *types.Customer, address *types.Address, extra map[string]any, createData func() map[string]any) (odooId int, isNew bool, err error) {
-	customerXid, _ := ShopifyIdToOdooXid(*customer.Id)
-	foundOdooCustomer, err := odoo.ReadRecordByXID("res.partner", customerXid, []string{"id"})
+func shopifyCustomerToOdoo(client *odoo.Client, customer *types.Customer, address *types.Address, extra map[string]any, createData func() map[string]any, topic string) (odooId int, isNew bool, err error) {
+	customerXid, _ := storeXid(*customer.Id, webhookStoreKey)
+	foundOdooCustomer, err := client.ReadRecordByXID("res.partner", customerXid, []string{"id"})
 	if err != nil {
 		return 0, false, fmt.Errorf("error checking customer from Odoo (XID=%s)\nERROR=%w", customerXid, err)
 	}
-	customerOdooData := mapShopifyCustomerToOdoo(customer, address, nil)
+	customerOdooData, err := mapShopifyCustomerToOdoo(client, customer, address, nil)
+	if err != nil {
+		return 0, false, err
+	}
 	maps.Copy(customerOdooData, extra)
 	if customerOdooData["name"] == customerOdooData["email"] || customerOdooData["country_id"] == nil || customerOdooData["country_id"] == 0 {
 		return 0, false, fmt.Errorf("missing information to process customer: name, email, and country are required")
 	}
+	if foundOdooCustomer == nil && createData != nil {
+		maps.Copy(customerOdooData, createData())
+	}
+	customerOdooData, err = client.CompatValues("res.partner", customerOdooData)
+	if err != nil {
+		return 0, false, err
+	}
 	if foundOdooCustomer == nil {
-		if createData != nil {
-			maps.Copy(customerOdooData, createData())
-		}
-		newId, err := odoo.Create("res.partner", customerOdooData, nil)
+		newId, isNew, err := client.CreateOrGetByXID("res.partner", customerXid, customerOdooData, nil)
 		if err != nil {
-			return 0, false, fmt.Errorf("error creating new customer in Odoo\nERROR=%w", err)
+			return 0, false, fmt.Errorf("error creating new customer %s in Odoo\nERROR=%w", customerXid, err)
 		}
-		err = odoo.AssignRecordXID("res.partner", newId, customerXid)
-		if err != nil {
-			odoo.Unlink("res.partner", newId, nil) // Try deleting newly created record as we won't be able to reference it without XID
-			return 0, false, fmt.Errorf("error assigning XID %s to new customer in Odoo\nERROR=%w", customerXid, err)
+		if isNew {
+			traceSync(client, topic, "res.partner", newId, traceTitle("Customer "+customer.DisplayName, true), nil, customerXid, customer)
 		}
-		return newId, true, nil
+		return newId, isNew, nil
 	}
 	foundId := int(foundOdooCustomer["id"].(float64))
-	err = odoo.Write("res.partner", foundId, customerOdooData, nil)
+	err = client.Write("res.partner", foundId, customerOdooData, nil)
 	if err != nil {
 		return 0, false, fmt.Errorf("error writing customer data in Odoo\nERROR=%w", err)
 	}
+	traceSync(client, topic, "res.partner", foundId, traceTitle("Customer "+customer.DisplayName, false), nil, customerXid, customer)
 	return foundId, false, nil
 }
 
-func ShopifyCompanyToOdoo(shopifyId string) (odooId int, isNew bool, err error) {
+// ShopifyCompanyToOdoo syncs a company, topic is the webhook topic mentioned in the trace,
+// if any.
+func ShopifyCompanyToOdoo(shopifyId, topic string) (odooId int, isNew bool, err error) {
+	client, stats, err := startSync()
+	subject := "company " + shopifyId
+	defer func() { stats.done(subject, err) }()
+	if err != nil {
+		return 0, false, err
+	}
 	company, err := adminapi.CompanyById(shopifyId)
 	if err != nil {
 		return 0, false, fmt.Errorf("error getting company from Shopify Admin API\nERROR=%w", err)
 	}
-	xid, _ := ShopifyIdToOdooXid(*company.Id)
-	found, err := odoo.ReadRecordByXID("res.partner", xid, []string{"id"})
+	subject = "company " + company.Name
+	xid, _ := storeXid(*company.Id, webhookStoreKey)
+	found, err := client.ReadRecordByXID("res.partner", xid, []string{"id"})
 	if err != nil {
 		return 0, false, fmt.Errorf("error checking company from Odoo (XID=%s)\nERROR=%w", xid, err)
 	}
@@ -211,9 +247,13 @@ func ShopifyCompanyToOdoo(shopifyId string) (odooId int, isNew bool, err error)
 	splitId := strings.Split(*company.Id, "/")
 	ref := "SHCC" + splitId[len(splitId)-1]
 
-	countryId, stateId := odoo.GetCountryAndStateIds(address.CountryCode(), address.ProvinceCode())
+	rc := referenceClient(client)
+	countryId, stateId, err := rc.ResolveCountryState(address.CountryCode(), address.ProvinceCode())
+	if err != nil {
+		return 0, false, fmt.Errorf("location address with invalid country or state for company %s\nERROR=%w", *company.Id, err)
+	}
 	if countryId == 0 || stateId == 0 {
-		return 0, false, fmt.Errorf("location address with invalid country or state for company %s (%d, %d)", *company.Id, countryId, stateId)
+		return 0, false, fmt.Errorf("location address without country or state for company %s (%d, %d)", *company.Id, countryId, stateId)
 	}
 
 	companyData := map[string]any{
@@ -236,64 +276,76 @@ func ShopifyCompanyToOdoo(shopifyId string) (odooId int, isNew bool, err error)
 	}
 	if found == nil {
 		createData := map[string]any{}
-		if ctype, err := odoo.SearchFirstId("customer.type", []any{[]any{"name", "=ilike", "business"}}, nil); err == nil && ctype != 0 {
+		if ctype, err := rc.SearchFirstId("customer.type", []any{[]any{"name", "=ilike", "business"}}, nil); err == nil && ctype != 0 {
 			createData["customer_type_id"] = ctype
 		}
-		if ctype, err := odoo.SearchFirstId("account.payment.method", []any{[]any{"name", "=ilike", "shopify"}}, nil); err == nil && ctype != 0 {
+		if ctype, err := rc.SearchFirstId("account.payment.method", []any{[]any{"name", "=ilike", "shopify"}}, nil); err == nil && ctype != 0 {
 			createData["customer_payment_method_id"] = ctype
 		}
-		if teams, err := odoo.SearchRead("crm.team", []any{[]any{"code", "=ilike", "leads"}}, []string{"id", "user_id"}, 1, nil); err == nil && len(teams) != 0 {
-			createData["team_id"] = int(helpers.Traverse(teams, []any{0, "id"}, 0.0))
-			createData["user_id"] = int(helpers.Traverse(teams, []any{0, "user_id", 0}, 0.0))
+		if teams, err := odoo.SearchReadInto[odooTeam](rc, "crm.team", []any{[]any{"code", "=ilike", "leads"}}, 1, nil); err == nil && len(teams) != 0 {
+			createData["team_id"] = teams[0].ID
+			createData["user_id"] = teams[0].User.ID
 		}
-		if qfw, err := odoo.GetIDByXID("website", "qfg.main_website"); err == nil && qfw != 0 {
+		if qfw, err := rc.GetIDByXID("website", "qfg.main_website"); err == nil && qfw != 0 {
 			createData["website_id"] = qfw
 		}
-		if wsp, err := odoo.GetIDByXID("product.pricelist", "qfg.pricelist_qf_wholesale"); err == nil && wsp != 0 {
+		if wsp, err := rc.GetIDByXID("product.pricelist", "qfg.pricelist_qf_wholesale"); err == nil && wsp != 0 {
 			createData["qf_pricelist_id"] = wsp
 		}
-		if wsp, err := odoo.GetIDByXID("product.pricelist", "qfg.pricelist_fm_wholesale"); err == nil && wsp != 0 {
+		if wsp, err := rc.GetIDByXID("product.pricelist", "qfg.pricelist_fm_wholesale"); err == nil && wsp != 0 {
 			createData["fm_pricelist_id"] = wsp
 		}
-		if src, err := odoo.FindFirstOrCreate("utm.source", []any{[]any{"name", "=ilike", "shopify"}}, map[string]any{"name": "Shopify"}, nil); err == nil {
+		if src, err := rc.FindFirstOrCreate("utm.source", []any{[]any{"name", "=ilike", "shopify"}}, map[string]any{"name": "Shopify"}, nil); err == nil {
 			createData["source_id"] = src
 		}
 		maps.Copy(companyData, createData)
-		newId, err := odoo.Create("res.partner", companyData, nil)
+	}
+	companyData, err = client.CompatValues("res.partner", companyData)
+	if err != nil {
+		return 0, false, err
+	}
+	if found == nil {
+		newId, isNew, err := client.CreateOrGetByXID("res.partner", xid, companyData, nil)
 		if err != nil {
-			return 0, false, fmt.Errorf("error creating new company in Odoo\nERROR=%w", err)
+			return 0, false, fmt.Errorf("error creating new company %s in Odoo\nERROR=%w", xid, err)
 		}
-		err = odoo.AssignRecordXID("res.partner", newId, xid)
-		if err != nil {
-			odoo.Unlink("res.partner", newId, nil) // Try deleting newly created record as we won't be able to reference it without XID
-			return 0, false, fmt.Errorf("error assigning XID %s to new company in Odoo\nERROR=%w", xid, err)
+		if isNew {
+			traceSync(client, topic, "res.partner", newId, traceTitle("Company "+company.Name, true), nil, xid, company)
 		}
-		return newId, true, nil
+		return newId, isNew, nil
 	}
 	foundId := int(found["id"].(float64))
-	err = odoo.Write("res.partner", foundId, companyData, nil)
+	err = client.Write("res.partner", foundId, companyData, nil)
 	if err != nil {
 		return 0, false, fmt.Errorf("error writing company data in Odoo\nERROR=%w", err)
 	}
+	traceSync(client, topic, "res.partner", foundId, traceTitle("Company "+company.Name, false), nil, xid, company)
 	return foundId, false, nil
 }
 
-func ensureShopifyCustomerAddressInOdoo(customerOdooId int, address *types.Address, addressType string) (addressId int, err error) {
-	addressMap := mapShopifyAddressToOdoo(address, map[string]any{
+func ensureShopifyCustomerAddressInOdoo(client *odoo.Client, storeKey string, customerOdooId int, address *types.Address, addressType string) (addressId int, err error) {
+	addressMap, err := mapShopifyAddressToOdoo(client, address, map[string]any{
 		"parent_id": customerOdooId,
 		"type":      addressType,
 	})
-	addressXid, _ := ShopifyIdToOdooXid(*address.Id)
-	addressId, err = odoo.GetIDByXID("res.partner", addressXid)
+	if err != nil {
+		return 0, err
+	}
+	addressMap, err = client.CompatValues("res.partner", addressMap)
+	if err != nil {
+		return 0, err
+	}
+	addressXid, _ := storeXid(*address.Id, storeKey)
+	addressId, err = client.GetIDByXID("res.partner", addressXid)
 	if err != nil {
 		return 0, fmt.Errorf("error searching for address %v\nERROR=%w", addressXid, err)
 	}
 	action := "updating"
 	if addressId == 0 {
 		action = "creating"
-		addressId, err = odoo.Create("res.partner", addressMap, map[string]any{"xid": addressXid})
+		addressId, _, err = client.CreateOrGetByXID("res.partner", addressXid, addressMap, nil)
 	} else {
-		err = odoo.Write("res.partner", addressId, addressMap, nil)
+		err = client.Write("res.partner", addressId, addressMap, nil)
 	}
 	if err != nil {
 		return addressId, fmt.Errorf("error %v address %v\nERROR=%w", action, addressXid, err)
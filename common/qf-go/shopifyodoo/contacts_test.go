@@ -0,0 +1,75 @@
+package shopifyodoo
+
+import (
+	"errors"
+	"qf/go/odoo"
+	"qf/go/odoo/odootest"
+	"qf/go/shopify/adminapi/types"
+	"testing"
+)
+
+const customerFixture = `{
+	"id": "gid://shopify/Customer/1001",
+	"displayName": "Jane Doe",
+	"defaultEmailAddress": {"emailAddress": "jane@example.com"},
+	"defaultPhoneNumber": {"phoneNumber": "+14165550100"},
+	"defaultAddress": {
+		"id": "gid://shopify/MailingAddress/2001",
+		"name": "Jane Doe",
+		"address1": "1 Front St",
+		"city": "Toronto",
+		"zip": "M5J 2X5",
+		"provinceCode": "ON",
+		"countryCodeV2": "CA"
+	}
+}`
+
+func TestShopifyCustomerToOdoo(t *testing.T) {
+	s := odootest.NewServer(t)
+	s.SeedFixtures()
+	client := s.SetDefault(t)
+	customer := decodeShopify[types.Customer](t, customerFixture)
+
+	odooId, isNew, err := shopifyCustomerToOdoo(client, customer, &customer.DefaultAddress, nil, nil, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !isNew || odooId == 0 {
+		t.Fatalf("expected new customer, got %v, %v", odooId, isNew)
+	}
+	if model, id := s.XID("__export__.shopify_customer_1001"); model != "res.partner" || id != odooId {
+		t.Fatalf("expected XID to point to the customer, got %v(%v)", model, id)
+	}
+	partner := s.Record("res.partner", odooId)
+	if partner["ref"] != "SHCU1001" || partner["email"] != "jane@example.com" || partner["mobile"] != "+14165550100" {
+		t.Fatalf("unexpected customer data: %v", partner)
+	}
+	countries := s.Records("res.country")
+	if partner["country_id"] != countries[0]["id"] || partner["state_id"] == nil {
+		t.Fatalf("expected customer in Canada with a province, got %v", partner)
+	}
+
+	customer.DisplayName = "Jane Smith"
+	updatedId, isNew, err := shopifyCustomerToOdoo(client, customer, &customer.DefaultAddress, nil, nil, "")
+	if err != nil || isNew || updatedId != odooId {
+		t.Fatalf("expected existing customer to be updated, got %v, %v, %v", updatedId, isNew, err)
+	}
+	if partners := s.Records("res.partner"); len(partners) != 1 || partners[0]["name"] != "Jane Smith" {
+		t.Fatalf("unexpected partners after update: %v", partners)
+	}
+}
+
+func TestShopifyCustomerToOdoo_MissingCountry(t *testing.T) {
+	s := odootest.NewServer(t)
+	s.SeedFixtures()
+	client := s.SetDefault(t)
+	customer := decodeShopify[types.Customer](t, customerFixture)
+	customer.DefaultAddress.CustomerCountryCode = "FR"
+
+	if _, _, err := shopifyCustomerToOdoo(client, customer, &customer.DefaultAddress, nil, nil, ""); !errors.Is(err, odoo.ErrNotFound) {
+		t.Fatalf("expected error for customer without a known country, got %v", err)
+	}
+	if partners := s.Records("res.partner"); len(partners) != 0 {
+		t.Fatalf("expected no partner to be created, got %v", partners)
+	}
+}
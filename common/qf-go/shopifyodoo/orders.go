@@ -1,7 +1,9 @@
 package shopifyodoo
 
 import (
+	"errors"
 	"fmt"
+	"log"
 	"maps"
 	"qf/go/helpers"
 	"qf/go/odoo"
@@ -12,7 +14,15 @@ import (
 	"time"
 )
 
-func computeScheduledDate(orderDate time.Time, companyId int, address *types.Address) (scheduledDate time.Time, err error) {
+// UnlinkPolicies are applied instead of deleting the records of a synced order, which
+// accountants may already have seen: discarded orders are cancelled, and lines removed in
+// Shopify are kept with a zero quantity.
+var UnlinkPolicies = map[string]odoo.UnlinkPolicy{
+	"sale.order":      odoo.CancelPolicy,
+	"sale.order.line": {Values: map[string]any{"product_uom_qty": 0}},
+}
+
+func computeScheduledDate(orderDate time.Time, companyId int, address *types.Address) (scheduledDate odoo.Datetime, err error) {
 	type LocationConfig struct {
 		Timezone string
 		Cities   []string
@@ -94,23 +104,33 @@ func computeScheduledDate(orderDate time.Time, companyId int, address *types.Add
 		localizedScheduled.Location(),
 	)
 
-	scheduledDate = localizedScheduled.UTC()
-	return scheduledDate, nil
+	return odoo.NewDatetime(localizedScheduled), nil
 }
 
-func shopifyTaxLinesToOdooIds(taxLines *[]types.OrderTaxLine, companyId int) ([]int, error) {
+func shopifyTaxLinesToOdooIds(oc *odoo.Client, taxLines *[]types.OrderTaxLine, companyId int) ([]int, error) {
 	taxes := make([]int, 0, len(*taxLines))
 	for _, taxLine := range *taxLines {
 		taxName := strings.ReplaceAll(fmt.Sprintf("%s %.2f%%", taxLine.Title, taxLine.RatePercentage), ".00%", "%")
 		taxData := map[string]any{
-			"name":         taxName,
-			"description":  taxName,
 			"amount_type":  "percent",
 			"type_tax_use": "sale",
 			"amount":       taxLine.RatePercentage,
 			"company_id":   companyId,
 		}
-		taxId, err := odoo.FindFirstOrCreate("account.tax", odoo.MapToDomain(taxData), taxData, nil)
+		taxDomain, err := odoo.MapToDomain(taxData)
+		if err != nil {
+			return nil, fmt.Errorf("error building tax domain for %v\nERROR=%w", taxName, err)
+		}
+		// Taxes may have been renamed in Odoo, so any of name or description matching is enough
+		taxDomain = odoo.And(taxDomain, odoo.Or(
+			odoo.Cond("name", odoo.OpEqILike, taxName),
+			odoo.Cond("description", odoo.OpEqILike, taxName),
+		))
+		maps.Copy(taxData, map[string]any{
+			"name":        taxName,
+			"description": taxName,
+		})
+		taxId, err := oc.FindFirstOrCreate("account.tax", taxDomain, taxData, nil)
 		if err != nil {
 			return nil, fmt.Errorf("error getting taxes %v for company %v\nERROR=%w", taxName, companyId, err)
 		}
@@ -119,20 +139,21 @@ func shopifyTaxLinesToOdooIds(taxLines *[]types.OrderTaxLine, companyId int) ([]
 	return taxes, nil
 }
 
-func shopifyOrderToOdoo(order *types.Order, customerOdooId int) (odooId int, isNew bool, err error) {
-	orderOdooXid, _ := ShopifyIdToOdooXid(*order.Id)
-	odooId, err = odoo.GetIDByXID("sale.order", orderOdooXid)
+// shopifyOrderToOdoo syncs an order of the store storeKey, whose XIDs it is qualified with.
+func shopifyOrderToOdoo(client *odoo.Client, order *types.Order, storeKey string, customerOdooId int, topic string) (odooId int, isNew bool, err error) {
+	orderOdooXid, _ := storeXid(*order.Id, storeKey)
+	odooId, err = client.GetIDByXID("sale.order", orderOdooXid)
 	if err != nil {
 		return 0, false, fmt.Errorf("error reading XID %v from Odoo\nERROR=%w", orderOdooXid, err)
 	}
 
-	shippingAddressOdooId, err := ensureShopifyCustomerAddressInOdoo(customerOdooId, &order.ShippingAddress, "delivery")
+	shippingAddressOdooId, err := ensureShopifyCustomerAddressInOdoo(client, storeKey, customerOdooId, &order.ShippingAddress, "delivery")
 	if err != nil {
 		return 0, false, fmt.Errorf("error getting the shipping address from Odoo\nERROR=%w", err)
 	}
 	billingAddressOdooId := shippingAddressOdooId
 	if *order.BillingAddress.Id != *order.ShippingAddress.Id {
-		billingAddressOdooId, err = ensureShopifyCustomerAddressInOdoo(customerOdooId, &order.BillingAddress, "invoice")
+		billingAddressOdooId, err = ensureShopifyCustomerAddressInOdoo(client, storeKey, customerOdooId, &order.BillingAddress, "invoice")
 		if err != nil {
 			return 0, false, fmt.Errorf("error getting the billing address from Odoo\nERROR=%w", err)
 		}
@@ -142,14 +163,22 @@ func shopifyOrderToOdoo(order *types.Order, customerOdooId int) (odooId int, isN
 	if strings.Contains(order.Name, "QF") {
 		companyId = odoo.CompanyQF
 	}
-	defer odoo.GlobalContext(map[string]any{"allowed_company_ids": []int{companyId}})()
+	oc := client.WithCompany(companyId)
+	for model, policy := range UnlinkPolicies {
+		oc = oc.WithUnlinkPolicy(model, policy)
+	}
+	rc := oc.Cached(ReferenceCacheTTL)
+	taxField, err := oc.FieldName("sale.order.line", "tax_id")
+	if err != nil {
+		return 0, false, err
+	}
 
 	orderData := map[string]any{
 		"partner_id":                     customerOdooId,
 		"partner_invoice_id":             billingAddressOdooId,
 		"partner_shipping_id":            shippingAddressOdooId,
 		"origin":                         order.Name,
-		"date_order":                     order.CreatedAt.Format(odoo.DateFormat),
+		"date_order":                     odoo.NewDatetime(order.CreatedAt),
 		"company_id":                     companyId,
 		"customer_delivery_instructions": order.DeliveryInstructions.Value,
 		"client_order_ref":               order.PurchaseOrderNumber.Value,
@@ -157,7 +186,7 @@ func shopifyOrderToOdoo(order *types.Order, customerOdooId int) (odooId int, isN
 		"amount_delivery":                0,
 		"no_handling_fee_reason":         "Shopify",
 	}
-	if src, err := odoo.FindFirstOrCreate("utm.source", []any{[]any{"name", "=ilike", "shopify"}}, map[string]any{"name": "Shopify"}, nil); err == nil {
+	if src, err := rc.FindFirstOrCreate("utm.source", []any{[]any{"name", "=ilike", "shopify"}}, map[string]any{"name": "Shopify"}, nil); err == nil {
 		orderData["source_id"] = src
 	}
 
@@ -176,7 +205,7 @@ func shopifyOrderToOdoo(order *types.Order, customerOdooId int) (odooId int, isN
 	idsBySku := map[string]int{}
 	skusById := map[int]string{}
 	if len(allSkus) != 0 {
-		products, err := odoo.SearchRead("product.product", []any{[]any{"default_code", "in", allSkus}}, []string{"id", "default_code"}, 0, map[string]any{"active_test": false})
+		products, err := oc.SearchRead("product.product", []any{[]any{"default_code", "in", allSkus}}, []string{"id", "default_code"}, 0, map[string]any{"active_test": false})
 		if err != nil {
 			return 0, false, fmt.Errorf("error reading products for the order %v in Odoo\nERROR=%w", orderOdooXid, err)
 		}
@@ -189,22 +218,40 @@ func shopifyOrderToOdoo(order *types.Order, customerOdooId int) (odooId int, isN
 		}
 	}
 
-	odooLineIds, err := odoo.SearchIds("sale.order.line", []any{[]any{"order_id", "=", odooId}}, nil)
+	existingLines := []odoo.Record{}
+	existingLineIds := []int{}
+	if odooId != 0 {
+		lineFields := []string{"id", "product_id", "name", "product_uom_qty", "price_unit", "sequence", "is_delivery", taxField}
+		lines, err := oc.SearchRead("sale.order.line", []any{[]any{"order_id", "=", odooId}}, lineFields, 0, nil)
+		if err != nil {
+			return 0, false, fmt.Errorf("error reading lines for the order %v in Odoo\nERROR=%w", orderOdooXid, err)
+		}
+		for _, line := range lines {
+			existingLines = append(existingLines, line)
+			existingLineIds = append(existingLineIds, helpers.JsonInt(line["id"]))
+		}
+	}
+
+	lineXids := make([]string, 0, order.Lines.Length()+1)
+	for _, shopifyLine := range order.Lines.Iter {
+		shopifyLineXid, _ := storeXid(*shopifyLine.Id, storeKey)
+		lineXids = append(lineXids, shopifyLineXid)
+	}
+	if order.ShippingLine.Id != nil {
+		shopifyLineXid, _ := storeXid(*order.ShippingLine.Id, storeKey)
+		lineXids = append(lineXids, shopifyLineXid)
+	}
+	lineIdsByXid, err := oc.GetIDsByXIDs("sale.order.line", lineXids)
 	if err != nil {
-		return 0, false, fmt.Errorf("error reading lines for the order %v in Odoo\nERROR=%w", orderOdooXid, err)
+		return 0, false, fmt.Errorf("error reading lines %v from Odoo Order %v\nERROR=%w", lineXids, orderOdooXid, err)
 	}
 
 	sequence := 1
-	odooOrderLines := make([]any, 0, max(order.Lines.Length(), len(odooLineIds)))
-	foundOdooLineIds := make([]int, 0, order.Lines.Length())
-	odooNewLinesData := map[string]map[string]any{}
+	desiredLines := make([]odoo.Record, 0, order.Lines.Length()+1)
+	newLineXids := []string{} // In the order of their creation
 	for _, shopifyLine := range order.Lines.Iter {
-		shopifyLineXid, _ := ShopifyIdToOdooXid(*shopifyLine.Id)
-		odooLineId, err := odoo.GetIDByXID("sale.order.line", shopifyLineXid)
-		if err != nil {
-			return 0, false, fmt.Errorf("error reading line %v from Odoo Order %v\nERROR=%w", shopifyLineXid, orderOdooXid, err)
-		}
-		odooLineData := map[string]any{
+		shopifyLineXid, _ := storeXid(*shopifyLine.Id, storeKey)
+		odooLineData := odoo.Record{
 			"product_id":      idsBySku[shopifyLine.Sku],
 			"name":            shopifyLine.Name,
 			"product_uom_qty": shopifyLine.Quantity,
@@ -212,24 +259,20 @@ func shopifyOrderToOdoo(order *types.Order, customerOdooId int) (odooId int, isN
 			"sequence":        sequence,
 		}
 		sequence += 1
-		taxes, err := shopifyTaxLinesToOdooIds(&shopifyLine.TaxLines, companyId)
+		taxes, err := shopifyTaxLinesToOdooIds(rc, &shopifyLine.TaxLines, companyId)
 		if err != nil {
 			return 0, false, fmt.Errorf("error creating line %v from Odoo Order %v\nERROR=%w", shopifyLineXid, orderOdooXid, err)
 		}
-		odooLineData["tax_id"] = []any{odoo.Command.Set(taxes)}
-		if odooLineId != 0 {
-			foundOdooLineIds = append(foundOdooLineIds, odooLineId)
-			odooOrderLines = append(odooOrderLines, odoo.Command.Update(odooLineId, odooLineData))
+		odooLineData[taxField] = []any{odoo.Command.Set(taxes)}
+		if odooLineId := lineIdsByXid[shopifyLineXid]; odooLineId != 0 {
+			odooLineData["id"] = odooLineId
 		} else {
-			odooNewLinesData[shopifyLineXid] = odooLineData
+			newLineXids = append(newLineXids, shopifyLineXid)
 		}
+		desiredLines = append(desiredLines, odooLineData)
 	}
 	if order.ShippingLine.Id != nil {
-		shopifyLineXid, _ := ShopifyIdToOdooXid(*order.ShippingLine.Id)
-		odooLineId, err := odoo.GetIDByXID("sale.order.line", shopifyLineXid)
-		if err != nil {
-			return 0, false, fmt.Errorf("error reading line %v from Odoo Order %v\nERROR=%w", shopifyLineXid, orderOdooXid, err)
-		}
+		shopifyLineXid, _ := storeXid(*order.ShippingLine.Id, storeKey)
 		carrierName := order.ShippingLine.Title
 		deliveryType := "base_on_rule"
 		if shippingSku == odoo.TwoshipSku {
@@ -245,13 +288,17 @@ func shopifyOrderToOdoo(order *types.Order, customerOdooId int) (odooId int, isN
 			"company_id":        false,
 			"integration_level": "rate",
 		}
-		carrier, err := odoo.FindFirstOrCreate("delivery.carrier", odoo.MapToDomain(carrierData), carrierData, nil)
+		carrierDomain, err := odoo.MapToDomain(carrierData)
+		if err != nil {
+			return 0, false, fmt.Errorf("error building delivery carrier domain for %v\nERROR=%w", carrierName, err)
+		}
+		carrier, err := rc.FindFirstOrCreate("delivery.carrier", carrierDomain, carrierData, nil)
 		if err != nil {
 			return 0, false, fmt.Errorf("error searching for delivery carrier %v for Odoo Order %v\nERROR=%w", carrierName, orderOdooXid, err)
 		}
 		orderData["carrier_id"] = carrier
 		orderData["amount_delivery"] = order.ShippingLine.Price.Amount()
-		odooLineData := map[string]any{
+		odooLineData := odoo.Record{
 			"product_id":      carrierProductId,
 			"name":            order.ShippingLine.Title,
 			"product_uom_qty": 1,
@@ -260,49 +307,71 @@ func shopifyOrderToOdoo(order *types.Order, customerOdooId int) (odooId int, isN
 			"sequence":        sequence,
 		}
 		sequence += 1
-		taxes, err := shopifyTaxLinesToOdooIds(&order.ShippingLine.TaxLines, companyId)
+		taxes, err := shopifyTaxLinesToOdooIds(rc, &order.ShippingLine.TaxLines, companyId)
 		if err != nil {
 			return 0, false, fmt.Errorf("error creating line %v from Odoo Order %v\nERROR=%w", shopifyLineXid, orderOdooXid, err)
 		}
-		odooLineData["tax_id"] = []any{odoo.Command.Set(taxes)}
-		if odooLineId != 0 {
-			foundOdooLineIds = append(foundOdooLineIds, odooLineId)
-			odooOrderLines = append(odooOrderLines, odoo.Command.Update(odooLineId, odooLineData))
+		odooLineData[taxField] = []any{odoo.Command.Set(taxes)}
+		if odooLineId := lineIdsByXid[shopifyLineXid]; odooLineId != 0 {
+			odooLineData["id"] = odooLineId
 		} else {
-			odooNewLinesData[shopifyLineXid] = odooLineData
+			newLineXids = append(newLineXids, shopifyLineXid)
 		}
+		desiredLines = append(desiredLines, odooLineData)
 	}
-	for _, odooLineId := range odooLineIds {
-		if !slices.Contains(foundOdooLineIds, odooLineId) {
-			odooOrderLines = append(odooOrderLines, odoo.Command.Delete(odooLineId))
-		}
+	// Lines removed by a previous sync are kept with a zero quantity, see UnlinkPolicies
+	knownLineIds := slices.AppendSeq([]int{}, maps.Values(lineIdsByXid))
+	existingLines = slices.DeleteFunc(existingLines, func(line odoo.Record) bool {
+		return line["product_uom_qty"] == 0.0 && !slices.Contains(knownLineIds, helpers.JsonInt(line["id"]))
+	})
+	// Unchanged lines are left out, lines without XID are deleted following UnlinkPolicies
+	lineCommands, err := odoo.DiffOne2Many(existingLines, desiredLines, odoo.ByID)
+	if err != nil {
+		return 0, false, fmt.Errorf("error comparing lines for the order %v in Odoo\nERROR=%w", orderOdooXid, err)
 	}
-	orderData["order_line"] = odooOrderLines
+	orderData["order_line"] = oc.SoftenCommands("sale.order.line", lineCommands)
 
 	if odooId == 0 {
 		createData := map[string]any{}
 		if scheduledDate, err := computeScheduledDate(order.CreatedAt, companyId, &order.ShippingAddress); err == nil {
-			createData["commitment_date"] = scheduledDate.Format(odoo.DateFormat)
+			createData["commitment_date"] = scheduledDate
 		}
 		maps.Copy(orderData, createData)
-		isNew = true
-		odooId, err = odoo.Create("sale.order", orderData, map[string]any{"xid": orderOdooXid})
+		odooId, isNew, err = oc.CreateOrGetByXID("sale.order", orderOdooXid, orderData, nil)
 		if err != nil {
 			return 0, false, fmt.Errorf("error creating the order %v in Odoo\nERROR=%w", orderOdooXid, err)
 		}
+		if !isNew {
+			// Created by a concurrent delivery of the same webhook, which also syncs its lines
+			return odooId, false, nil
+		}
 	} else {
-		err = odoo.Write("sale.order", odooId, orderData, nil)
+		err = oc.Write("sale.order", odooId, orderData, nil)
 		if err != nil {
 			return 0, false, fmt.Errorf("error updating the order %v in Odoo\nERROR=%w", orderOdooXid, err)
 		}
 	}
 
 	lineErrors := ""
-	for lineXid, lineData := range odooNewLinesData {
-		lineData["order_id"] = odooId
-		_, err := odoo.Create("sale.order.line", lineData, map[string]any{"xid": lineXid})
+	// A dry-run order only exists in the plan, its new lines cannot be read back
+	if len(newLineXids) != 0 && !oc.IsDryRun() {
+		// New lines were created by the order write in the order of their commands, so with
+		// increasing IDs. Zeroed lines may share their sequence, so it cannot identify them.
+		lineIds, err := oc.SearchIds("sale.order.line", odoo.Cond("order_id", odoo.OpEq, odooId), nil)
 		if err != nil {
-			lineErrors += fmt.Sprintf("error creating new line %v for order %v in Odoo\nERROR=%v", lineXid, orderOdooXid, err)
+			return odooId, false, fmt.Errorf("error reading new lines for order %v in Odoo\nERROR=%w", orderOdooXid, err)
+		}
+		newLineIds := slices.DeleteFunc(lineIds, func(id int) bool {
+			return slices.Contains(existingLineIds, id)
+		})
+		if len(newLineIds) != len(newLineXids) {
+			return odooId, false, fmt.Errorf("expected %d new lines for order %v in Odoo, found %v", len(newLineXids), orderOdooXid, newLineIds)
+		}
+		slices.Sort(newLineIds)
+		for i, lineId := range newLineIds {
+			if err := oc.AssignRecordXID("sale.order.line", lineId, newLineXids[i]); err != nil {
+				lineErrors += fmt.Sprintf("error assigning XID to new line %v for order %v in Odoo\nERROR=%v\n", newLineXids[i], orderOdooXid, err)
+			}
 		}
 	}
 	lineErrors = strings.Trim(lineErrors, " \n")
@@ -312,33 +381,102 @@ func shopifyOrderToOdoo(order *types.Order, customerOdooId int) (odooId int, isN
 
 	if isNew {
 		confirmationContext := map[string]any{"followup_validation": false, "skip_preauth_payment": true}
-		confirmationRes, err := odoo.JsonRpcExecuteKw("sale.order", "action_confirm", []any{[]any{odooId}}, map[string]any{"context": confirmationContext})
+		confirmed, err := odoo.Call[bool](oc, "sale.order", "action_confirm", []int{odooId}, nil, map[string]any{"context": confirmationContext})
 		if err != nil {
-			odoo.Unlink("sale.order", odooId, nil) // Try to delete order as we could not confirm it
+			discardOrder(oc, odooId) // Try to discard order as we could not confirm it
+			var rpcError *odoo.RPCError
+			if errors.As(err, &rpcError) && (rpcError.IsException(odoo.ExceptionUser) || rpcError.IsException(odoo.ExceptionValidation)) {
+				return 0, false, fmt.Errorf("order %v was rejected by Odoo on confirmation: %s\nERROR=%w", orderOdooXid, rpcError.Detail, err)
+			}
+			var wizardError *odoo.WizardError
+			if errors.As(err, &wizardError) {
+				return 0, false, fmt.Errorf("order %v was not confirmed, Odoo asked for the wizard %v\nERROR=%w", orderOdooXid, wizardError.Action.ResModel, err)
+			}
 			return 0, false, fmt.Errorf("error confirming the order %v in Odoo\nERROR=%w", orderOdooXid, err)
 		}
-		res, err := odoo.SearchReadById("sale.order", odooId, []string{"state"}, nil)
-		if err != nil {
-			odoo.Unlink("sale.order", odooId, nil) // Try to delete order as we could not validate the confirmation
-			return 0, false, fmt.Errorf("error validating order confirmation %v in Odoo\nERROR=%w", orderOdooXid, err)
-		}
-		if res["state"].(string) != "sale" {
-			odoo.Unlink("sale.order", odooId, nil) // Try to delete order as we could not validate the confirmation
-			return 0, false, fmt.Errorf("could not validate order confirmation %v in Odoo. Expected=sale, Got=%v. Result: %v", orderOdooXid, res["state"], confirmationRes)
+		// A dry-run order only exists in the plan, its confirmation cannot be read back
+		if !oc.IsDryRun() {
+			res, err := oc.SearchReadById("sale.order", odooId, []string{"state"}, nil)
+			if err != nil {
+				discardOrder(oc, odooId) // Try to discard order as we could not validate the confirmation
+				return 0, false, fmt.Errorf("error validating order confirmation %v in Odoo\nERROR=%w", orderOdooXid, err)
+			}
+			if res["state"].(string) != "sale" {
+				discardOrder(oc, odooId) // Try to discard order as we could not validate the confirmation
+				return 0, false, fmt.Errorf("could not validate order confirmation %v in Odoo. Expected=sale, Got=%v. Result: %v", orderOdooXid, res["state"], confirmed)
+			}
 		}
 	}
 
+	traceSync(oc, topic, "sale.order", odooId, traceTitle("Order "+order.Name, isNew), []string{
+		orderLinesSummary(lineCommands),
+		fmt.Sprintf("Untaxed total: %.2f", untaxedTotal(order)),
+	}, orderOdooXid, order)
 	return odooId, isNew, nil
 }
 
-func ShopifyOrderToOdoo(shopifyId string) (odooId int, isNew bool, err error) {
+// discardOrder unlinks an order that could not be confirmed, following UnlinkPolicies, and
+// removes its XID so the next sync creates it again.
+func discardOrder(oc *odoo.Client, odooId int) {
+	if err := oc.Unlink("sale.order", odooId, nil); err != nil {
+		log.Printf("error discarding order %v: %v", odooId, err)
+		return
+	}
+	xidIds, err := oc.SearchIds("ir.model.data", odoo.And(
+		odoo.Cond("model", odoo.OpEq, "sale.order"),
+		odoo.Cond("res_id", odoo.OpEq, odooId),
+	), nil)
+	if err == nil && len(xidIds) != 0 {
+		err = oc.UnlinkMulti("ir.model.data", xidIds, nil)
+	}
+	if err != nil {
+		log.Printf("error removing XID of discarded order %v: %v", odooId, err)
+	}
+}
+
+// syncedOrderId returns the Shopify order an FM order is synced from, the QF order when
+// the FM order was placed for it, with the key of its store.
+func syncedOrderId(order *types.Order) (shopifyId, storeKey string) {
+	if qfId := order.CustomAttribute("FarMetOrderId"); qfId != "" {
+		return "gid://shopify/Order/" + qfId, "QF"
+	}
+	return *order.Id, webhookStoreKey
+}
+
+// fullShopifyOrder fetches the full order the FM order is synced from, and returns the key
+// of the store it comes from.
+func fullShopifyOrder(order *types.Order) (fullOrder *types.Order, storeKey string, err error) {
+	shopifyId, storeKey := syncedOrderId(order)
+	if storeKey == webhookStoreKey {
+		fullOrder, err = adminapi.OrderById(shopifyId)
+	} else {
+		adminapi.AsQF(func() {
+			fullOrder, err = adminapi.OrderById(shopifyId)
+		})
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("error getting %v order %v from Shopify Admin API\nERROR=%w", storeKey, shopifyId, err)
+	}
+	return fullOrder, storeKey, nil
+}
+
+// ShopifyOrderToOdoo syncs an order of the FM store, topic is the webhook topic mentioned in
+// the trace, if any.
+func ShopifyOrderToOdoo(shopifyId, topic string) (odooId int, isNew bool, err error) {
+	client, stats, err := startSync()
+	subject := "order " + shopifyId
+	defer func() { stats.done(subject, err) }()
+	if err != nil {
+		return 0, false, err
+	}
 	order, err := adminapi.OrderMinimalById(shopifyId)
 	if err != nil {
 		return 0, false, fmt.Errorf("error getting order %v from Shopify Admin API\nERROR=%w", shopifyId, err)
 	}
+	subject = "order " + order.Name
 
-	customerOdooXid, _ := ShopifyIdToOdooXid(*order.Customer.Id)
-	customerOdooId, err := odoo.GetIDByXID("res.partner", customerOdooXid)
+	customerOdooXid, _ := storeXid(*order.Customer.Id, webhookStoreKey)
+	customerOdooId, err := client.GetIDByXID("res.partner", customerOdooXid)
 	if err != nil {
 		return 0, false, fmt.Errorf("error getting customer %w from Odoo", err)
 	}
@@ -346,22 +484,9 @@ func ShopifyOrderToOdoo(shopifyId string) (odooId int, isNew bool, err error) {
 		return 0, false, fmt.Errorf("customer %v not found in Odoo", customerOdooXid)
 	}
 
-	var fullOrder *types.Order
-	qfId := order.CustomAttribute("FarMetOrderId")
-	if qfId != "" {
-		qfShopifyId := "gid://shopify/Order/" + qfId
-		adminapi.AsQF(func() {
-			fullOrder, err = adminapi.OrderById(qfShopifyId)
-		})
-		if err != nil {
-			return 0, false, fmt.Errorf("error getting QF order %v from Shopify Admin API\nERROR=%w", qfShopifyId, err)
-		}
-	} else {
-		fullOrder, err = adminapi.OrderById(shopifyId)
-		if err != nil {
-			return 0, false, fmt.Errorf("error getting FM order %v from Shopify Admin API\nERROR=%w", shopifyId, err)
-		}
+	fullOrder, storeKey, err := fullShopifyOrder(order)
+	if err != nil {
+		return 0, false, err
 	}
-
-	return shopifyOrderToOdoo(fullOrder, customerOdooId)
+	return shopifyOrderToOdoo(client, fullOrder, storeKey, customerOdooId, topic)
 }
@@ -0,0 +1,312 @@
+package shopifyodoo
+
+import (
+	"encoding/json"
+	"qf/go/helpers"
+	"qf/go/odoo"
+	"qf/go/odoo/odootest"
+	"qf/go/shopify/adminapi"
+	"qf/go/shopify/adminapi/types"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const orderFixture = `{
+	"id": "gid://shopify/Order/3001",
+	"name": "#QF3001",
+	"createdAt": "2025-06-10T10:00:00-04:00",
+	"deliveryInstructions": {"key": "Delivery Instructions", "value": "Back door"},
+	"purchaseOrder": {"key": "PO", "value": "PO-42"},
+	"shippingAddress": {"id": "gid://shopify/MailingAddress/2001", "name": "Jane Doe", "address1": "1 Front St", "city": "Toronto", "zip": "M5J 2X5", "provinceCode": "ON", "countryCodeV2": "CA"},
+	"billingAddress": {"id": "gid://shopify/MailingAddress/2001", "name": "Jane Doe", "address1": "1 Front St", "city": "Toronto", "zip": "M5J 2X5", "provinceCode": "ON", "countryCodeV2": "CA"},
+	"lineItems": {"edges": [
+		{"node": {"id": "gid://shopify/LineItem/4001", "name": "Saffron", "sku": "SAF-1", "currentQuantity": 2, "discountedUnitPriceSet": {"shopMoney": {"amount": "25.00"}}, "taxLines": [{"title": "HST", "ratePercentage": 13}]}},
+		{"node": {"id": "gid://shopify/LineItem/4002", "name": "Vanilla", "sku": "VAN-1", "currentQuantity": 1, "discountedUnitPriceSet": {"shopMoney": {"amount": "40.00"}}, "taxLines": []}}
+	]},
+	"shippingLine": {"id": "gid://shopify/ShippingLine/5001", "title": "Standard", "source": "shopify", "discountedPriceSet": {"shopMoney": {"amount": "15.00"}}, "taxLines": [{"title": "HST", "ratePercentage": 13}]}
+}`
+
+func TestShopifyOrderToOdoo(t *testing.T) {
+	s := odootest.NewServer(t)
+	s.SeedFixtures()
+	idsBySku := s.SeedProducts("SAF-1", "VAN-1")
+	customerId := s.Seed("res.partner", odootest.Record{"name": "Jane Doe"})[0]
+	client := s.SetDefault(t)
+	order := decodeShopify[types.Order](t, orderFixture)
+
+	odooId, isNew, err := shopifyOrderToOdoo(client, order, "FM", customerId, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !isNew || odooId == 0 {
+		t.Fatalf("expected new order, got %v, %v", odooId, isNew)
+	}
+	saleOrder := s.Record("sale.order", odooId)
+	if saleOrder["state"] != "sale" || saleOrder["company_id"] != 2.0 || saleOrder["client_order_ref"] != "PO-42" || saleOrder["commitment_date"] == nil {
+		t.Fatalf("unexpected order: %v", saleOrder)
+	}
+	if saleOrder["date_order"] != "2025-06-10 14:00:00" || saleOrder["commitment_date"] != "2025-06-11 16:00:00" {
+		t.Fatalf("expected dates in UTC, got %v and %v", saleOrder["date_order"], saleOrder["commitment_date"])
+	}
+	if model, id := s.XID("__export__.shopify_order_3001"); model != "sale.order" || id != odooId {
+		t.Fatalf("expected XID to point to the order, got %v(%v)", model, id)
+	}
+
+	lines := s.Records("sale.order.line")
+	if len(lines) != 3 {
+		t.Fatalf("expected 2 product lines and a delivery line, got %v", lines)
+	}
+	products := []float64{}
+	for _, line := range lines {
+		products = append(products, line["product_id"].(float64))
+		if line["order_id"] != float64(odooId) {
+			t.Fatalf("line not linked to the order: %v", line)
+		}
+	}
+	if !slices.Contains(products, float64(idsBySku["SAF-1"])) || !slices.Contains(products, float64(idsBySku["VAN-1"])) {
+		t.Fatalf("unexpected line products: %v", products)
+	}
+	_, saffronLineId := s.XID("__export__.shopify_lineitem_4001")
+	if taxes := s.Record("sale.order.line", saffronLineId)["tax_id"].([]any); len(taxes) != 1 {
+		t.Fatalf("expected HST on the saffron line, got %v", taxes)
+	}
+	if carriers := s.Records("delivery.carrier"); len(carriers) != 1 || saleOrder["carrier_id"] != carriers[0]["id"] {
+		t.Fatalf("expected delivery carrier to be created and set, got %v", carriers)
+	}
+
+	// Vanilla removed from the order
+	order.Lines.Edges = order.Lines.Edges[:1]
+	updatedId, isNew, err := shopifyOrderToOdoo(client, order, "FM", customerId, "")
+	if err != nil || isNew || updatedId != odooId {
+		t.Fatalf("expected existing order to be updated, got %v, %v, %v", updatedId, isNew, err)
+	}
+	_, vanillaLineId := s.XID("__export__.shopify_lineitem_4002")
+	if lines := s.Records("sale.order.line"); len(lines) != 3 || s.Record("sale.order.line", vanillaLineId)["product_uom_qty"] != 0.0 {
+		t.Fatalf("expected the removed line to be kept with a zero quantity, got %v", lines)
+	}
+	calls := s.Calls()
+	for _, call := range slices.Backward(calls) {
+		if call.Model == "sale.order" && call.Method == "write" {
+			// The saffron line is unchanged, the delivery line moves up
+			commands := call.Args[1].(map[string]any)["order_line"].([]any)
+			if len(commands) != 2 || !reflect.DeepEqual(commands[0].([]any)[2], map[string]any{"sequence": 2.0}) || !reflect.DeepEqual(commands[1], []any{1.0, float64(vanillaLineId), map[string]any{"product_uom_qty": 0.0}}) {
+				t.Fatalf("expected the delivery line sequence update and the removed line zeroed, got %v", commands)
+			}
+			break
+		}
+	}
+
+	// Synced again, the zeroed line is left as is
+	if _, _, err := shopifyOrderToOdoo(client, order, "FM", customerId, ""); err != nil {
+		t.Fatal(err)
+	}
+	for _, call := range slices.Backward(s.Calls()) {
+		if call.Model == "sale.order" && call.Method == "write" {
+			if commands := call.Args[1].(map[string]any)["order_line"].([]any); len(commands) != 0 {
+				t.Fatalf("expected no line changes, got %v", commands)
+			}
+			break
+		}
+	}
+	if orders := s.Records("sale.order"); len(orders) != 1 {
+		t.Fatalf("expected a single order, got %v", orders)
+	}
+}
+
+func TestShopifyOrderToOdoo_ReplacedLine(t *testing.T) {
+	s := odootest.NewServer(t)
+	s.SeedFixtures()
+	s.SeedProducts("SAF-1", "VAN-1")
+	customerId := s.Seed("res.partner", odootest.Record{"name": "Jane Doe"})[0]
+	client := s.SetDefault(t)
+	order := decodeShopify[types.Order](t, orderFixture)
+	if _, _, err := shopifyOrderToOdoo(client, order, "FM", customerId, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, vanillaLineId := s.XID("__export__.shopify_lineitem_4002")
+
+	// The vanilla line is replaced by a new line at the same position, so with the same
+	// sequence as the zeroed one
+	newLineId := "gid://shopify/LineItem/4003"
+	order.Lines.Edges[1].Node.Id = &newLineId
+	if _, _, err := shopifyOrderToOdoo(client, order, "FM", customerId, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, replacementLineId := s.XID("__export__.shopify_lineitem_4003")
+	if replacementLineId == 0 || replacementLineId == vanillaLineId {
+		t.Fatalf("expected the XID on a new line, got %v (replaced line %v)", replacementLineId, vanillaLineId)
+	}
+	if line := s.Record("sale.order.line", replacementLineId); line["product_uom_qty"] != 1.0 {
+		t.Fatalf("expected the XID on the new line, got %v", line)
+	}
+	if line := s.Record("sale.order.line", vanillaLineId); line["product_uom_qty"] != 0.0 {
+		t.Fatalf("expected the replaced line to be zeroed, got %v", line)
+	}
+
+	// Synced again, the lines are left as is
+	if _, _, err := shopifyOrderToOdoo(client, order, "FM", customerId, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if lines := s.Records("sale.order.line"); len(lines) != 4 {
+		t.Fatalf("expected the 3 lines and the zeroed one, got %v", lines)
+	}
+}
+
+// fakeOrdersAdminAPI answers the order queries of the Admin API with the given order nodes
+// by GID, and returns the domains queried.
+func fakeOrdersAdminAPI(t *testing.T, nodes map[string]string) *[]string {
+	t.Helper()
+	t.Cleanup(helpers.TempEnvVars(map[string]string{
+		"SHOPIFY_DOMAIN_FM":                 "fm.myshopify.com",
+		"SHOPIFY_ADMIN_API_ACCESS_TOKEN_FM": "fm-token",
+		"SHOPIFY_DOMAIN_QF":                 "qf.myshopify.com",
+		"SHOPIFY_ADMIN_API_ACCESS_TOKEN_QF": "qf-token",
+	}))
+	domains := []string{}
+	t.Cleanup(adminapi.QueryConfig.SetGraphQLQuery(func(url, header, token, query string, vars map[string]any) (any, error) {
+		domains = append(domains, strings.Split(url, "/")[2])
+		var order any
+		if node, found := nodes[vars["id"].(string)]; found {
+			if err := json.Unmarshal([]byte(node), &order); err != nil {
+				t.Fatalf("invalid Shopify fixture: %v", err)
+			}
+		}
+		return map[string]any{"data": map[string]any{"order": order}}, nil
+	}, false))
+	return &domains
+}
+
+func TestShopifyOrderToOdoo_QFOrder(t *testing.T) {
+	s := odootest.NewServer(t)
+	s.SeedFixtures()
+	s.SeedProducts("SAF-1", "VAN-1")
+	customerId := s.Seed("res.partner", odootest.Record{"name": "Jane Doe"})[0]
+	s.SeedXID("qf.shop_fm_customer_7001", "res.partner", customerId)
+	s.SetDefault(t)
+	defer helpers.TempSet(&XidConfig, &XidNamespace{Module: "qf", Prefix: "shop", PerStore: true})()
+	domains := fakeOrdersAdminAPI(t, map[string]string{
+		"gid://shopify/Order/9001": `{"id": "gid://shopify/Order/9001", "name": "#FM9001", "customer": {"id": "gid://shopify/Customer/7001"}, "customAttributes": [{"key": "FarMetOrderId", "value": "3001"}]}`,
+		"gid://shopify/Order/3001": orderFixture,
+	})
+
+	odooId, isNew, err := ShopifyOrderToOdoo("gid://shopify/Order/9001", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !isNew || odooId == 0 {
+		t.Fatalf("expected new order, got %v, %v", odooId, isNew)
+	}
+	if want := []string{"fm.myshopify.com", "qf.myshopify.com"}; !slices.Equal(*domains, want) {
+		t.Fatalf("expected the minimal order from FM and the full order from QF, got %v", *domains)
+	}
+	for xid, model := range map[string]string{
+		"qf.shop_qf_order_3001":          "sale.order",
+		"qf.shop_qf_lineitem_4001":       "sale.order.line",
+		"qf.shop_qf_shippingline_5001":   "sale.order.line",
+		"qf.shop_qf_mailingaddress_2001": "res.partner",
+	} {
+		if xidModel, _ := s.XID(xid); xidModel != model {
+			t.Fatalf("expected QF store XID %v for a %v, got %v", xid, model, xidModel)
+		}
+	}
+	if model, _ := s.XID("qf.shop_fm_order_3001"); model != "" {
+		t.Fatalf("expected no FM store XID for the QF order, got a %v", model)
+	}
+}
+
+func TestShopifyOrderToOdoo_DryRun(t *testing.T) {
+	s := odootest.NewServer(t)
+	s.SeedFixtures()
+	s.SeedProducts("SAF-1", "VAN-1")
+	customerId := s.Seed("res.partner", odootest.Record{"name": "Jane Doe"})[0]
+	s.SeedXID("__export__.shopify_customer_7001", "res.partner", customerId)
+	plan := odoo.NewPlan()
+	t.Cleanup(odoo.SetDefault(s.Client(t).WithDryRun(plan)))
+	fakeOrdersAdminAPI(t, map[string]string{
+		"gid://shopify/Order/3001": strings.Replace(orderFixture, `"name": "#QF3001",`, `"name": "#QF3001", "customer": {"id": "gid://shopify/Customer/7001"},`, 1),
+	})
+
+	odooId, isNew, err := ShopifyOrderToOdoo("gid://shopify/Order/3001", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !isNew || odooId >= 0 {
+		t.Fatalf("expected a new order with a placeholder ID, got %v, %v", odooId, isNew)
+	}
+	methods := []string{}
+	for _, call := range plan.Calls() {
+		if call.Model == "sale.order" {
+			methods = append(methods, call.Method)
+		}
+	}
+	if want := []string{"create", "action_confirm"}; !slices.Equal(methods, want) {
+		t.Fatalf("expected the order to be created and confirmed in the plan, got %v", methods)
+	}
+	for _, call := range s.Calls() {
+		if slices.Contains([]string{"create", "write", "unlink", "action_confirm"}, call.Method) {
+			t.Fatalf("expected only reads to reach Odoo, got %v.%v", call.Model, call.Method)
+		}
+	}
+	if orders := s.Records("sale.order"); len(orders) != 0 {
+		t.Fatalf("expected no order to be created, got %v", orders)
+	}
+}
+
+func TestShopifyOrderToOdoo_UnknownProduct(t *testing.T) {
+	s := odootest.NewServer(t)
+	s.SeedFixtures()
+	s.SeedProducts("SAF-1")
+	customerId := s.Seed("res.partner", odootest.Record{"name": "Jane Doe"})[0]
+	client := s.SetDefault(t)
+	order := decodeShopify[types.Order](t, orderFixture)
+
+	if _, _, err := shopifyOrderToOdoo(client, order, "FM", customerId, ""); err == nil {
+		t.Fatalf("expected error for a product missing in Odoo")
+	}
+	if orders := s.Records("sale.order"); len(orders) != 0 {
+		t.Fatalf("expected no order to be created, got %v", orders)
+	}
+}
+
+func TestShopifyOrderToOdoo_Trace(t *testing.T) {
+	s := odootest.NewServer(t)
+	s.SeedFixtures()
+	s.SeedProducts("SAF-1", "VAN-1")
+	customerId := s.Seed("res.partner", odootest.Record{"name": "Jane Doe"})[0]
+	client := s.SetDefault(t)
+	defer helpers.TempSet(&Trace, &TraceConfig{Enabled: true, AttachPayload: true})()
+	order := decodeShopify[types.Order](t, orderFixture)
+
+	odooId, _, err := shopifyOrderToOdoo(client, order, "FM", customerId, "orders/create")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	messages := s.Records("mail.message")
+	if len(messages) != 1 || messages[0]["model"] != "sale.order" || messages[0]["res_id"] != float64(odooId) {
+		t.Fatalf("expected a message on the order, got %v", messages)
+	}
+	body := messages[0]["body"].(string)
+	for _, expected := range []string{"Order #QF3001 created from Shopify", "Lines: 3 added", "Untaxed total: 105.00", "Webhook: orders/create"} {
+		if !strings.Contains(body, expected) {
+			t.Fatalf("expected %q in the message, got %v", expected, body)
+		}
+	}
+	attachments := s.Records("ir.attachment")
+	if len(attachments) != 1 || attachments[0]["name"] != "shopify_order_3001.json" || !reflect.DeepEqual(messages[0]["attachment_ids"], []any{attachments[0]["id"]}) {
+		t.Fatalf("expected the payload attached to the message, got %v attachments, %v", len(attachments), messages[0]["attachment_ids"])
+	}
+	// Vanilla removed from the order, the line is zeroed but reported as removed
+	order.Lines.Edges = order.Lines.Edges[:1]
+	if _, _, err := shopifyOrderToOdoo(client, order, "FM", customerId, "orders/updated"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	messages = s.Records("mail.message")
+	if len(messages) != 2 || !strings.Contains(messages[1]["body"].(string), "Lines: 1 updated, 1 removed") {
+		t.Fatalf("expected the removed line in the update message, got %v", messages)
+	}
+	if !strings.Contains(messages[1]["body"].(string), "Webhook: orders/updated") {
+		t.Fatalf("expected the topic of the update in the message, got %v", messages[1]["body"])
+	}
+}
@@ -2,10 +2,111 @@ package shopifyodoo
 
 import (
 	"fmt"
+	"log"
+	"os"
+	"qf/go/odoo"
+	"qf/go/shopify/adminapi"
 	"strings"
+	"time"
 )
 
-func ShopifyIdToOdooXid(shopifyId string) (string, error) {
+// Reference data (countries, taxes, teams, pricelists...) rarely changes, so its lookups
+// are cached for ReferenceCacheTTL, across warm invocations too.
+var ReferenceCacheTTL = 15 * time.Minute
+
+func referenceClient(client *odoo.Client) *odoo.Client {
+	return client.Cached(ReferenceCacheTTL)
+}
+
+// syncStats counts the Odoo calls of a sync, made through the client of startSync.
+type syncStats struct {
+	counter odoo.CallCounter
+	start   time.Time
+}
+
+// startSync returns the client a sync makes its Odoo calls through, counting them without
+// affecting the default client, so concurrent syncs are counted apart.
+func startSync() (*odoo.Client, *syncStats, error) {
+	stats := &syncStats{start: time.Now()}
+	client, err := odoo.Default()
+	if err != nil {
+		return nil, stats, err
+	}
+	return client.WithInterceptors(stats.counter.Intercept), stats, nil
+}
+
+// done logs the calls, e.g. "order #QF1234 synced in 42 calls / 3.1s".
+func (s *syncStats) done(subject string, err error) {
+	calls, _, _ := s.counter.Calls()
+	elapsed := time.Since(s.start).Seconds()
+	if err != nil {
+		log.Printf("%s failed after %d calls / %.1fs", subject, calls, elapsed)
+		return
+	}
+	log.Printf("%s synced in %d calls / %.1fs", subject, calls, elapsed)
+}
+
+// XidNamespace is where the external IDs of Shopify records live in Odoo, e.g.
+// __export__.shopify_order_123, or shopify_sync.shopify_qf_order_123 per store.
+type XidNamespace struct {
+	Module   string
+	Prefix   string
+	PerStore bool // Qualify names with the store key, so IDs of the QF and FM stores cannot collide
+}
+
+// LegacyXidNamespace is the namespace used before it was configurable.
+var LegacyXidNamespace = XidNamespace{Module: "__export__", Prefix: "shopify"}
+
+// XidConfig overrides the namespace read from the environment when set.
+var XidConfig *XidNamespace
+
+// XidNamespaceFromEnv reads the namespace from SHOPIFY_XID_MODULE, SHOPIFY_XID_PREFIX and
+// SHOPIFY_XID_PER_STORE=true, defaulting to LegacyXidNamespace.
+func XidNamespaceFromEnv() XidNamespace {
+	namespace := LegacyXidNamespace
+	if module := os.Getenv("SHOPIFY_XID_MODULE"); module != "" {
+		namespace.Module = module
+	}
+	if prefix := os.Getenv("SHOPIFY_XID_PREFIX"); prefix != "" {
+		namespace.Prefix = prefix
+	}
+	namespace.PerStore = os.Getenv("SHOPIFY_XID_PER_STORE") == "true"
+	return namespace
+}
+
+func currentXidNamespace() XidNamespace {
+	if XidConfig != nil {
+		return *XidConfig
+	}
+	return XidNamespaceFromEnv()
+}
+
+// webhookStoreKey is the store sending the webhooks, whose customers, companies, orders
+// and transactions are synced first. Orders placed for QF are synced from the QF store.
+const webhookStoreKey = "FM"
+
+// currentStoreKey is the store queried by the Admin API, FM unless within adminapi.AsQF.
+func currentStoreKey() string {
+	if adminapi.QueryConfig.DomainKey == "" {
+		return "FM"
+	}
+	return adminapi.QueryConfig.DomainKey
+}
+
+// Name returns the ir.model.data name of a Shopify object, the store key is ignored
+// unless PerStore is set.
+func (n XidNamespace) Name(objectType, idNumber, storeKey string) (string, error) {
+	if !n.PerStore {
+		return fmt.Sprintf("%s_%s_%s", n.Prefix, objectType, idNumber), nil
+	}
+	if storeKey == "" {
+		return "", fmt.Errorf("missing store key for XID of Shopify %s %s", objectType, idNumber)
+	}
+	return fmt.Sprintf("%s_%s_%s_%s", n.Prefix, strings.ToLower(storeKey), objectType, idNumber), nil
+}
+
+// Xid returns the complete external ID of a Shopify GID.
+func (n XidNamespace) Xid(shopifyId, storeKey string) (string, error) {
 	parts := strings.Split(shopifyId, "/")
 	if len(parts) != 5 {
 		return "", fmt.Errorf("invalid Shopify ID: %v", shopifyId)
@@ -15,5 +116,39 @@ func ShopifyIdToOdooXid(shopifyId string) (string, error) {
 	if idNumber == "" || objectType == "" {
 		return "", fmt.Errorf("invalid Shopify ID: %v", shopifyId)
 	}
-	return fmt.Sprintf("__export__.shopify_%s_%s", objectType, idNumber), nil
+	name, err := n.Name(objectType, idNumber, storeKey)
+	if err != nil {
+		return "", err
+	}
+	return n.Module + "." + name, nil
+}
+
+// parseName splits an ir.model.data name of the namespace into its object type and ID.
+func (n XidNamespace) parseName(name string) (objectType, idNumber string, ok bool) {
+	rest, found := strings.CutPrefix(name, n.Prefix+"_")
+	if !found {
+		return "", "", false
+	}
+	parts := strings.Split(rest, "_")
+	if n.PerStore {
+		if len(parts) != 3 {
+			return "", "", false
+		}
+		parts = parts[1:]
+	}
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+// ShopifyIdToOdooXid returns the external ID of a Shopify GID in the configured namespace,
+// for the store currently queried.
+func ShopifyIdToOdooXid(shopifyId string) (string, error) {
+	return currentXidNamespace().Xid(shopifyId, currentStoreKey())
+}
+
+// storeXid returns the external ID of a Shopify GID of the store storeKey.
+func storeXid(shopifyId, storeKey string) (string, error) {
+	return currentXidNamespace().Xid(shopifyId, storeKey)
 }
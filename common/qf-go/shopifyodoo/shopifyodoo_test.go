@@ -1,7 +1,17 @@
 package shopifyodoo
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"qf/go/helpers"
+	"qf/go/odoo"
+	"qf/go/odoo/odootest"
+	"qf/go/shopify/adminapi"
 	"qf/go/shopify/adminapi/types"
+	"strings"
 	"testing"
 	"time"
 )
@@ -68,6 +78,58 @@ func TestShopifyIdToOdooXid_KO(t *testing.T) {
 	}
 }
 
+func TestShopifyIdToOdooXid_Namespace(t *testing.T) {
+	defer helpers.TempEnvVars(map[string]string{"SHOPIFY_XID_MODULE": "shopify_sync", "SHOPIFY_XID_PREFIX": "", "SHOPIFY_XID_PER_STORE": "true"})()
+	gid := "gid://shopify/Order/123"
+	if xid, _ := ShopifyIdToOdooXid(gid); xid != "shopify_sync.shopify_fm_order_123" {
+		t.Fatalf("expected FM store XID, got %v", xid)
+	}
+	adminapi.AsQF(func() {
+		if xid, _ := ShopifyIdToOdooXid(gid); xid != "shopify_sync.shopify_qf_order_123" {
+			t.Fatalf("expected QF store XID, got %v", xid)
+		}
+	})
+
+	defer helpers.TempSet(&XidConfig, &XidNamespace{Module: "qf", Prefix: "shop"})()
+	if xid, _ := ShopifyIdToOdooXid(gid); xid != "qf.shop_order_123" {
+		t.Fatalf("expected XID of the configured namespace, got %v", xid)
+	}
+	if _, err := (XidNamespace{Module: "qf", Prefix: "shop", PerStore: true}).Xid(gid, ""); err == nil {
+		t.Fatalf("expected error without store key")
+	}
+}
+
+func TestSyncStats(t *testing.T) {
+	s := odootest.NewServer(t)
+	defaultClient := s.SetDefault(t)
+	logs := &bytes.Buffer{}
+	log.SetOutput(logs)
+	defer log.SetOutput(os.Stderr)
+
+	// Interleaved syncs count their own calls only
+	first, firstStats, err := startSync()
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, secondStats, _ := startSync()
+	first.SearchCount("res.partner", []any{}, nil)
+	second.SearchCount("res.partner", []any{}, nil)
+	first.SearchCount("res.partner", []any{}, nil)
+	firstStats.done("order #QF1234", nil)
+	secondStats.done("order #QF1235", errors.New("failed"))
+	odoo.SearchCount("res.partner", []any{}, nil)
+
+	if !strings.Contains(logs.String(), "order #QF1234 synced in 2 calls / ") || !strings.Contains(logs.String(), "order #QF1235 failed after 1 calls") {
+		t.Fatalf("unexpected logs:\n%s", logs)
+	}
+	if current, _ := odoo.Default(); current != defaultClient {
+		t.Fatalf("expected the default client to be left as is")
+	}
+	if calls, _, _ := firstStats.counter.Calls(); calls != 2 {
+		t.Fatalf("expected calls after the sync not to be counted, got %v", calls)
+	}
+}
+
 func TestComputeScheduleDate(t *testing.T) {
 	testCases := []struct {
 		Title        string
@@ -168,9 +230,19 @@ func TestComputeScheduleDate(t *testing.T) {
 				t.Fatalf("Unexpected error parsing date %v: %v", tc.ExpectedDate, err)
 			}
 			expected = time.Date(expected.Year(), expected.Month(), expected.Day(), 12, 0, 0, 0, tz).UTC()
-			if res != expected {
+			if !res.Equal(expected) || res.Location() != time.UTC {
 				t.Fatalf("Incorrect resulting date. Expected=%v, Got=%v", expected, res)
 			}
 		})
 	}
 }
+
+// decodeShopify decodes an Admin API response node into T.
+func decodeShopify[T any](t *testing.T, node string) *T {
+	t.Helper()
+	var result T
+	if err := json.Unmarshal([]byte(node), &result); err != nil {
+		t.Fatalf("invalid Shopify fixture: %v", err)
+	}
+	return &result
+}
@@ -0,0 +1,127 @@
+package shopifyodoo
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"log"
+	"os"
+	"qf/go/odoo"
+	"qf/go/shopify/adminapi/types"
+	"strings"
+)
+
+// TraceConfig configures the trace left in the chatter of the records synced.
+type TraceConfig struct {
+	Enabled       bool // Post a note summarizing the sync
+	AttachPayload bool // Attach the Shopify payload to the note, as JSON
+}
+
+// Trace overrides the configuration read from the environment when set.
+var Trace *TraceConfig
+
+// TraceConfigFromEnv reads SHOPIFY_ODOO_TRACE=true and SHOPIFY_ODOO_TRACE_PAYLOAD=true.
+func TraceConfigFromEnv() TraceConfig {
+	return TraceConfig{
+		Enabled:       os.Getenv("SHOPIFY_ODOO_TRACE") == "true",
+		AttachPayload: os.Getenv("SHOPIFY_ODOO_TRACE_PAYLOAD") == "true",
+	}
+}
+
+func currentTraceConfig() TraceConfig {
+	if Trace != nil {
+		return *Trace
+	}
+	return TraceConfigFromEnv()
+}
+
+// traceSync posts a note with the title and details on the record synced, with the
+// payload attached under the name of its XID, and the webhook topic when known. The sync
+// is done at this point, so errors are only logged. A nil client is the default one.
+func traceSync(client *odoo.Client, topic string, model string, id int, title string, details []string, xid string, payload any) {
+	config := currentTraceConfig()
+	if !config.Enabled || id == 0 {
+		return
+	}
+	if client == nil {
+		var err error
+		if client, err = odoo.Default(); err != nil {
+			log.Printf("error posting sync trace on %v(%v): %v", model, id, err)
+			return
+		}
+	}
+	if topic != "" {
+		details = append(details, "Webhook: "+topic)
+	}
+	body := "<p>" + html.EscapeString(title) + "</p>"
+	if len(details) != 0 {
+		body += "<ul>"
+		for _, detail := range details {
+			body += "<li>" + html.EscapeString(detail) + "</li>"
+		}
+		body += "</ul>"
+	}
+
+	message := odoo.Message{Body: body}
+	if config.AttachPayload && payload != nil {
+		content, err := json.MarshalIndent(payload, "", "  ")
+		if err == nil {
+			var attachmentId int
+			_, name, _ := strings.Cut(xid, ".")
+			attachmentId, err = client.Attach(model, id, name+".json", content, "application/json")
+			message.AttachmentIds = []int{attachmentId}
+		}
+		if err != nil {
+			log.Printf("error attaching Shopify payload to %v(%v): %v", model, id, err)
+			message.AttachmentIds = nil
+		}
+	}
+	if _, err := client.MessagePost(model, id, message); err != nil {
+		log.Printf("error posting sync trace on %v(%v): %v", model, id, err)
+	}
+}
+
+func traceTitle(subject string, isNew bool) string {
+	if isNew {
+		return subject + " created from Shopify"
+	}
+	return subject + " updated from Shopify"
+}
+
+// orderLinesSummary describes the changes of the order_line commands, before they are
+// softened, so lines removed are reported as such.
+func orderLinesSummary(commands []any) string {
+	counts := map[int]int{}
+	for _, command := range commands {
+		if command, ok := command.([]any); ok && len(command) != 0 {
+			if code, ok := command[0].(int); ok {
+				counts[code]++
+			}
+		}
+	}
+	changes := []string{}
+	for _, change := range []struct {
+		code  int
+		label string
+	}{{0, "added"}, {1, "updated"}, {2, "removed"}} {
+		if counts[change.code] != 0 {
+			changes = append(changes, fmt.Sprintf("%d %s", counts[change.code], change.label))
+		}
+	}
+	if len(changes) == 0 {
+		return "Lines: unchanged"
+	}
+	return "Lines: " + strings.Join(changes, ", ")
+}
+
+// untaxedTotal is the total of the Shopify order lines and shipping, without taxes.
+func untaxedTotal(order *types.Order) float64 {
+	total := 0.0
+	for _, line := range order.Lines.Iter {
+		total += float64(line.Quantity) * line.UnitPrice.Amount()
+	}
+	if order.ShippingLine.Id != nil {
+		total += order.ShippingLine.Price.Amount()
+	}
+	return total
+}
@@ -1,24 +1,41 @@
 package shopifyodoo
 
 import (
+	"errors"
 	"fmt"
-	"qf/go/helpers"
 	"qf/go/odoo"
 	"qf/go/shopify/adminapi"
 	"qf/go/shopify/adminapi/types"
 	"strings"
-	"time"
 )
 
-func shopifyTransactionToOdoo[T types.OrderTransactionInterface](order *types.Order, transaction T, setState string) (txOdooId int, isNew bool, err error) {
+type odooTransaction struct {
+	ID    int    `odoo:"id"`
+	State string `odoo:"state"`
+}
+
+type odooOrder struct {
+	ID                int           `odoo:"id"`
+	Name              string        `odoo:"name"`
+	Company           odoo.Many2One `odoo:"company_id"`
+	CommercialPartner odoo.Many2One `odoo:"commercial_partner_id"`
+}
+
+// shopifyTransactionToOdoo syncs a transaction of the webhook store to the Odoo order synced
+// from orderShopifyId, of the store storeKey, see syncedOrderId.
+func shopifyTransactionToOdoo[T types.OrderTransactionInterface](client *odoo.Client, orderShopifyId, storeKey string, transaction T, setState string) (txOdooId int, isNew bool, err error) {
 	txShopifyId := *transaction.GetId()
-	txOdooXid, _ := ShopifyIdToOdooXid(txShopifyId)
-	txOdooRes, err := odoo.ReadRecordByXID("payment.transaction", txOdooXid, []string{"id", "state"})
+	txOdooXid, _ := storeXid(txShopifyId, webhookStoreKey)
+	txOdooRes, err := client.ReadRecordByXID("payment.transaction", txOdooXid, odoo.FieldsOf[odooTransaction]())
 	if err != nil {
 		return 0, false, fmt.Errorf("error getting transaction %v from Odoo\nERROR=%w", txOdooXid, err)
 	}
-	txOdooId = int(helpers.Traverse(txOdooRes, []any{"id"}, 0.0))
-	txOdooState := helpers.Traverse(txOdooRes, []any{"state"}, "")
+	txOdoo, err := odoo.DecodeRecord[odooTransaction](txOdooRes)
+	if err != nil {
+		return 0, false, fmt.Errorf("error decoding transaction %v from Odoo\nERROR=%w", txOdooXid, err)
+	}
+	txOdooId = txOdoo.ID
+	txOdooState := txOdoo.State
 	if txOdooId != 0 && txOdooState == "" {
 		return 0, false, fmt.Errorf("incorrect data from transaction %v from Odoo (id: %v, state: %v)", txOdooXid, txOdooId, txOdooState)
 	}
@@ -28,27 +45,39 @@ func shopifyTransactionToOdoo[T types.OrderTransactionInterface](order *types.Or
 		return txOdooId, false, nil
 	}
 
-	orderOdooXid, _ := ShopifyIdToOdooXid(*order.Id)
-	orderOdooRes, err := odoo.ReadRecordByXID("sale.order", orderOdooXid, []string{"id", "company_id", "commercial_partner_id", "name"})
+	orderOdooXid, _ := storeXid(orderShopifyId, storeKey)
+	orderOdooRes, err := client.ReadRecordByXID("sale.order", orderOdooXid, odoo.FieldsOf[odooOrder]())
 	if err != nil || orderOdooRes == nil {
 		return 0, false, fmt.Errorf("error getting order %v from Odoo\nERROR=%w", orderOdooXid, err)
 	}
-	orderOdooId := int(helpers.Traverse(orderOdooRes, []any{"id"}, 0.0))
-	companyOdooId := int(helpers.Traverse(orderOdooRes, []any{"company_id", 0}, 0.0))
-	partnerOdooId := int(helpers.Traverse(orderOdooRes, []any{"commercial_partner_id", 0}, 0.0))
-	orderName := helpers.Traverse(orderOdooRes, []any{"name"}, "")
+	orderOdoo, err := odoo.DecodeRecord[odooOrder](orderOdooRes)
+	if err != nil {
+		return 0, false, fmt.Errorf("error decoding order %v from Odoo\nERROR=%w", orderOdooXid, err)
+	}
+	orderOdooId := orderOdoo.ID
+	companyOdooId := orderOdoo.Company.ID
+	partnerOdooId := orderOdoo.CommercialPartner.ID
+	orderName := orderOdoo.Name
 	if orderOdooId == 0 || companyOdooId == 0 || partnerOdooId == 0 || orderName == "" {
 		return 0, false, fmt.Errorf("incorrect data from order %v from Odoo (id: %v, company_id: %v, commercial_partner_id: %v, name: %v)", orderOdooXid, orderOdooId, companyOdooId, partnerOdooId, orderName)
 	}
 
-	defer odoo.GlobalContext(map[string]any{"allowed_company_ids": []int{companyOdooId}})()
+	oc := client.WithCompany(companyOdooId)
+	rc := oc.Cached(ReferenceCacheTTL)
 
-	currency, err := odoo.SearchId("res.currency", []any{[]any{"name", "=", "CAD"}}, nil)
+	currency, err := rc.SearchId("res.currency", []any{[]any{"name", "=", "CAD"}}, nil)
+	if errors.Is(err, odoo.ErrNotFound) || errors.Is(err, odoo.ErrMultipleResults) {
+		return 0, false, fmt.Errorf("currency CAD could not be uniquely identified in Odoo\nERROR=%w", err)
+	}
 	if err != nil {
 		return 0, false, fmt.Errorf("error getting currency CAD from Odoo\nERROR=%w", err)
 	}
 
-	acquirer, err := odoo.SearchFirstId("payment.acquirer", []any{[]any{"company_id", "=", companyOdooId}, []any{"name", "=ilike", "shopify"}}, nil)
+	acquirerModel, err := oc.ModelName("payment.acquirer")
+	if err != nil {
+		return 0, false, err
+	}
+	acquirer, err := rc.SearchFirstId(acquirerModel, []any{[]any{"company_id", "=", companyOdooId}, []any{"name", "=ilike", "shopify"}}, nil)
 	if err != nil || acquirer == 0 {
 		return 0, false, fmt.Errorf("error getting acquirer Shopify from Odoo\nERROR=%w", err)
 	}
@@ -70,20 +99,23 @@ func shopifyTransactionToOdoo[T types.OrderTransactionInterface](order *types.Or
 		"partner_id":         partnerOdooId,
 		"acquirer_reference": txShopifyIdNumber,
 		"state":              setState,
-		"last_state_change":  time.Now().UTC().Format(odoo.DateFormat),
+		"last_state_change":  odoo.Now(),
 	}
 	if amount == 0 || setState == "cancel" {
 		txData["state"] = "cancel"
 	}
+	txData, err = oc.CompatValues("payment.transaction", txData)
+	if err != nil {
+		return 0, false, err
+	}
 
 	if txOdooId == 0 {
-		isNew = true
-		txOdooId, err = odoo.Create("payment.transaction", txData, map[string]any{"xid": txOdooXid})
+		txOdooId, isNew, err = oc.CreateOrGetByXID("payment.transaction", txOdooXid, txData, nil)
 		if err != nil {
 			return 0, false, fmt.Errorf("error creating transaction %v in Odoo\nERROR=%w", txOdooXid, err)
 		}
 	} else {
-		err := odoo.Write("payment.transaction", txOdooId, txData, nil)
+		err := oc.Write("payment.transaction", txOdooId, txData, nil)
 		if err != nil {
 			return 0, false, fmt.Errorf("error updating transaction %v in Odoo\nERROR=%w", txOdooXid, err)
 		}
@@ -92,33 +124,38 @@ func shopifyTransactionToOdoo[T types.OrderTransactionInterface](order *types.Or
 	return txOdooId, isNew, nil
 }
 
-func handleTransactionAuthorization(order *types.Order, transaction types.OrderTransaction) (odooId int, isNew bool, err error) {
-	return shopifyTransactionToOdoo(order, transaction, "authorized")
+func handleTransactionAuthorization(client *odoo.Client, orderShopifyId, storeKey string, transaction types.OrderTransaction) (odooId int, isNew bool, err error) {
+	return shopifyTransactionToOdoo(client, orderShopifyId, storeKey, transaction, "authorized")
 }
 
-func handleTransactionCapture(order *types.Order, transaction types.OrderTransaction) (odooId int, isNew bool, err error) {
-	odooId, isNew, err = shopifyTransactionToOdoo(order, transaction, "done")
+func handleTransactionCapture(client *odoo.Client, orderShopifyId, storeKey string, transaction types.OrderTransaction) (odooId int, isNew bool, err error) {
+	odooId, isNew, err = shopifyTransactionToOdoo(client, orderShopifyId, storeKey, transaction, "done")
 	if err != nil {
 		return odooId, isNew, err
 	}
 	if transaction.ParentTransaction.Id != nil {
-		_, _, err = shopifyTransactionToOdoo(order, transaction.ParentTransaction, "authorized")
+		_, _, err = shopifyTransactionToOdoo(client, orderShopifyId, storeKey, transaction.ParentTransaction, "authorized")
 	}
 	return odooId, isNew, err
 }
 
-func handleTransactionSale(order *types.Order, transaction types.OrderTransaction) (odooId int, isNew bool, err error) {
-	return shopifyTransactionToOdoo(order, transaction, "done")
+func handleTransactionSale(client *odoo.Client, orderShopifyId, storeKey string, transaction types.OrderTransaction) (odooId int, isNew bool, err error) {
+	return shopifyTransactionToOdoo(client, orderShopifyId, storeKey, transaction, "done")
 }
 
-func handleTransactionVoid(order *types.Order, transaction types.OrderTransaction) (odooId int, isNew bool, err error) {
+func handleTransactionVoid(client *odoo.Client, orderShopifyId, storeKey string, transaction types.OrderTransaction) (odooId int, isNew bool, err error) {
 	if transaction.ParentTransaction.Id != nil {
-		return shopifyTransactionToOdoo(order, transaction.ParentTransaction, "cancel")
+		return shopifyTransactionToOdoo(client, orderShopifyId, storeKey, transaction.ParentTransaction, "cancel")
 	}
 	return 0, false, nil
 }
 
 func ShopifyTransactionToOdoo(orderShopifyId string, transactionShopifyId string) (odooId int, isNew bool, err error) {
+	client, stats, err := startSync()
+	defer func() { stats.done("transaction "+transactionShopifyId, err) }()
+	if err != nil {
+		return 0, false, err
+	}
 	order, err := adminapi.OrderWithTransactionsById(orderShopifyId)
 	if err != nil {
 		return 0, false, fmt.Errorf("error getting order %v from Shopify Admin API\nERROR=%w", orderShopifyId, err)
@@ -139,15 +176,16 @@ func ShopifyTransactionToOdoo(orderShopifyId string, transactionShopifyId string
 		return 0, false, nil
 	}
 
+	syncedOrderShopifyId, storeKey := syncedOrderId(order)
 	switch transaction.Kind {
 	case "AUTHORIZATION":
-		return handleTransactionAuthorization(order, transaction)
+		return handleTransactionAuthorization(client, syncedOrderShopifyId, storeKey, transaction)
 	case "CAPTURE":
-		return handleTransactionCapture(order, transaction)
+		return handleTransactionCapture(client, syncedOrderShopifyId, storeKey, transaction)
 	case "SALE":
-		return handleTransactionSale(order, transaction)
+		return handleTransactionSale(client, syncedOrderShopifyId, storeKey, transaction)
 	case "VOID":
-		return handleTransactionVoid(order, transaction)
+		return handleTransactionVoid(client, syncedOrderShopifyId, storeKey, transaction)
 	}
 
 	// Unsupported transaction, ignore
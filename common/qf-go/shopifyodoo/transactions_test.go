@@ -0,0 +1,112 @@
+package shopifyodoo
+
+import (
+	"qf/go/helpers"
+	"qf/go/odoo/odootest"
+	"qf/go/shopify/adminapi/types"
+	"reflect"
+	"testing"
+)
+
+const transactionFixture = `{
+	"id": "gid://shopify/OrderTransaction/6001",
+	"kind": "AUTHORIZATION",
+	"status": "SUCCESS",
+	"amountSet": {"shopMoney": {"amount": "120.50"}},
+	"totalUnsettledSet": {"shopMoney": {"amount": "120.50"}}
+}`
+
+func TestShopifyTransactionToOdoo(t *testing.T) {
+	s := odootest.NewServer(t)
+	s.SeedFixtures()
+	customerId := s.Seed("res.partner", odootest.Record{"name": "Jane Doe"})[0]
+	orderId := s.Seed("sale.order", odootest.Record{"name": "S00042", "company_id": 2, "partner_id": customerId, "commercial_partner_id": customerId})[0]
+	s.SeedXID("__export__.shopify_order_3001", "sale.order", orderId)
+	client := s.SetDefault(t)
+	transaction := decodeShopify[types.OrderTransaction](t, transactionFixture)
+
+	txId, isNew, err := shopifyTransactionToOdoo(client, "gid://shopify/Order/3001", "FM", *transaction, "authorized")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !isNew || txId == 0 {
+		t.Fatalf("expected new transaction, got %v, %v", txId, isNew)
+	}
+	tx := s.Record("payment.transaction", txId)
+	if tx["reference"] != "S00042-6001" || tx["state"] != "authorized" || tx["amount"] != 120.5 || tx["acquirer_reference"] != "6001" {
+		t.Fatalf("unexpected transaction: %v", tx)
+	}
+	acquirers := s.Records("payment.acquirer")
+	if tx["acquirer_id"] != acquirers[0]["id"] || tx["partner_id"] != float64(customerId) {
+		t.Fatalf("expected transaction with the QF Shopify acquirer and the customer, got %v", tx)
+	}
+
+	updatedId, isNew, err := shopifyTransactionToOdoo(client, "gid://shopify/Order/3001", "FM", *transaction, "done")
+	if err != nil || isNew || updatedId != txId {
+		t.Fatalf("expected authorized transaction to be updated, got %v, %v, %v", updatedId, isNew, err)
+	}
+	if state := s.Record("payment.transaction", txId)["state"]; state != "done" {
+		t.Fatalf("expected done transaction, got %v", state)
+	}
+
+	// Done transactions cannot change anymore
+	if _, _, err := shopifyTransactionToOdoo(client, "gid://shopify/Order/3001", "FM", *transaction, "cancel"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if state := s.Record("payment.transaction", txId)["state"]; state != "done" {
+		t.Fatalf("expected done transaction to be left untouched, got %v", state)
+	}
+}
+
+func TestShopifyTransactionToOdoo_ProviderOn16(t *testing.T) {
+	s := odootest.NewServer(t)
+	s.Version = "16.0"
+	s.SeedFixtures()
+	s.Seed("payment.provider", odootest.Record{"name": "Shopify", "company_id": 2})
+	customerId := s.Seed("res.partner", odootest.Record{"name": "Jane Doe"})[0]
+	orderId := s.Seed("sale.order", odootest.Record{"name": "S00042", "company_id": 2, "partner_id": customerId, "commercial_partner_id": customerId})[0]
+	s.SeedXID("__export__.shopify_order_3001", "sale.order", orderId)
+	client := s.SetDefault(t)
+	transaction := decodeShopify[types.OrderTransaction](t, transactionFixture)
+
+	txId, _, err := shopifyTransactionToOdoo(client, "gid://shopify/Order/3001", "FM", *transaction, "authorized")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tx := s.Record("payment.transaction", txId)
+	if tx["provider_id"] == nil || tx["provider_reference"] != "6001" || tx["acquirer_id"] != nil {
+		t.Fatalf("expected provider fields on Odoo 16, got %v", tx)
+	}
+}
+
+func TestShopifyTransactionToOdoo_QFOrder(t *testing.T) {
+	s := odootest.NewServer(t)
+	s.SeedFixtures()
+	s.SeedProducts("SAF-1", "VAN-1")
+	customerId := s.Seed("res.partner", odootest.Record{"name": "Jane Doe"})[0]
+	s.SeedXID("qf.shop_fm_customer_7001", "res.partner", customerId)
+	s.SetDefault(t)
+	defer helpers.TempSet(&XidConfig, &XidNamespace{Module: "qf", Prefix: "shop", PerStore: true})()
+	fakeOrdersAdminAPI(t, map[string]string{
+		"gid://shopify/Order/9001": `{"id": "gid://shopify/Order/9001", "name": "#FM9001", "customer": {"id": "gid://shopify/Customer/7001"}, "customAttributes": [{"key": "FarMetOrderId", "value": "3001"}], "transactions": [` + transactionFixture + `]}`,
+		"gid://shopify/Order/3001": orderFixture,
+	})
+
+	orderId, _, err := ShopifyOrderToOdoo("gid://shopify/Order/9001", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	txId, isNew, err := ShopifyTransactionToOdoo("gid://shopify/Order/9001", "gid://shopify/OrderTransaction/6001")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !isNew || txId == 0 {
+		t.Fatalf("expected new transaction, got %v, %v", txId, isNew)
+	}
+	if orders := s.Record("payment.transaction", txId)["sale_order_ids"]; !reflect.DeepEqual(orders, []any{float64(orderId)}) {
+		t.Fatalf("expected the transaction on the QF order %v, got %v", orderId, orders)
+	}
+	if model, id := s.XID("qf.shop_fm_ordertransaction_6001"); model != "payment.transaction" || id != txId {
+		t.Fatalf("expected the XID of the FM transaction, got %v(%v)", model, id)
+	}
+}
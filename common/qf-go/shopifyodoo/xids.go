@@ -0,0 +1,63 @@
+package shopifyodoo
+
+import (
+	"fmt"
+	"qf/go/odoo"
+)
+
+// XidStoreKey returns the store key of a record being migrated, e.g. from the company of a
+// sale.order. Only needed when the target namespace is per store.
+type XidStoreKey func(model string, resId int) (string, error)
+
+type odooModelData struct {
+	ID    int    `odoo:"id"`
+	Name  string `odoo:"name"`
+	Model string `odoo:"model"`
+	ResId int    `odoo:"res_id"`
+}
+
+// MigrateXids renames the ir.model.data rows of the from namespace into the to namespace,
+// returning the number of rows renamed. Rows already in the to namespace are left as is, so
+// it can be run again after a failure. Pass a dry-run client to preview the writes.
+func MigrateXids(client *odoo.Client, from, to XidNamespace, storeKey XidStoreKey) (int, error) {
+	if client == nil {
+		var err error
+		if client, err = odoo.Default(); err != nil {
+			return 0, err
+		}
+	}
+	if to.PerStore && storeKey == nil {
+		return 0, fmt.Errorf("a store key function is required to migrate XIDs to a per store namespace")
+	}
+	domain := odoo.And(odoo.Cond("module", odoo.OpEq, from.Module), odoo.Cond("name", "=like", from.Prefix+"_%"))
+	rows, err := odoo.SearchReadInto[odooModelData](client, "ir.model.data", domain, 0, nil)
+	if err != nil {
+		return 0, fmt.Errorf("error reading XIDs of module %v from Odoo\nERROR=%w", from.Module, err)
+	}
+	migrated := 0
+	for _, row := range rows {
+		objectType, idNumber, ok := from.parseName(row.Name)
+		if !ok {
+			continue
+		}
+		key := ""
+		if to.PerStore {
+			if key, err = storeKey(row.Model, row.ResId); err != nil {
+				return migrated, fmt.Errorf("error getting the store of %v %v\nERROR=%w", row.Model, row.ResId, err)
+			}
+		}
+		newName, err := to.Name(objectType, idNumber, key)
+		if err != nil {
+			return migrated, err
+		}
+		if to.Module == from.Module && newName == row.Name {
+			continue
+		}
+		err = client.Write("ir.model.data", row.ID, map[string]any{"module": to.Module, "name": newName}, nil)
+		if err != nil {
+			return migrated, fmt.Errorf("error renaming XID %v.%v to %v.%v\nERROR=%w", from.Module, row.Name, to.Module, newName, err)
+		}
+		migrated++
+	}
+	return migrated, nil
+}
@@ -0,0 +1,46 @@
+package shopifyodoo
+
+import (
+	"qf/go/odoo"
+	"qf/go/odoo/odootest"
+	"testing"
+)
+
+func TestMigrateXids(t *testing.T) {
+	s := odootest.NewServer(t)
+	s.SeedCompanies()
+	qfOrder := s.Seed("sale.order", odootest.Record{"name": "S00001", "company_id": odoo.CompanyQF})[0]
+	fmOrder := s.Seed("sale.order", odootest.Record{"name": "S00002", "company_id": odoo.CompanyFM})[0]
+	s.SeedXID("__export__.shopify_order_1001", "sale.order", qfOrder)
+	s.SeedXID("__export__.shopify_order_1002", "sale.order", fmOrder)
+	s.SeedXID("__export__.sale_order_manual", "sale.order", fmOrder)
+	client := s.Client(t)
+
+	to := XidNamespace{Module: "shopify_sync", Prefix: "shopify", PerStore: true}
+	storeKey := func(model string, resId int) (string, error) {
+		order, err := client.SearchReadById(model, resId, []string{"company_id"}, nil)
+		if err != nil {
+			return "", err
+		}
+		if order["company_id"].([]any)[0] == float64(odoo.CompanyQF) {
+			return "QF", nil
+		}
+		return "FM", nil
+	}
+	migrated, err := MigrateXids(client, LegacyXidNamespace, to, storeKey)
+	if err != nil || migrated != 2 {
+		t.Fatalf("expected 2 migrated XIDs, got %v, %v", migrated, err)
+	}
+	for xid, expected := range map[string]int{
+		"shopify_sync.shopify_qf_order_1001": qfOrder,
+		"shopify_sync.shopify_fm_order_1002": fmOrder,
+		"__export__.sale_order_manual":       fmOrder,
+	} {
+		if _, id := s.XID(xid); id != expected {
+			t.Fatalf("expected %v to point to %v, got %v", xid, expected, id)
+		}
+	}
+	if migrated, err := MigrateXids(client, LegacyXidNamespace, to, storeKey); err != nil || migrated != 0 {
+		t.Fatalf("expected nothing left to migrate, got %v, %v", migrated, err)
+	}
+}
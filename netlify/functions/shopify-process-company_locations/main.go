@@ -27,7 +27,7 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (*event
 		return qfn.NetlifyLogAndResponse(400, "Company GraphQL ID not in request body", nil)
 	}
 
-	odooId, isNew, err := shopifyodoo.ShopifyCompanyToOdoo(companyId.(string))
+	odooId, isNew, err := shopifyodoo.ShopifyCompanyToOdoo(companyId.(string), qfn.WebhookTopic(request))
 	if err != nil {
 		return qfn.NetlifyLogAndResponse(500, "Error processing company", err)
 	}
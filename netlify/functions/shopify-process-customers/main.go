@@ -23,7 +23,7 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (*event
 		return qfn.NetlifyLogAndResponse(400, "Customer Admin API ID not in request body", nil)
 	}
 
-	odooId, isNew, err := shopifyodoo.ShopifyCustomerToOdoo(customerId.(string))
+	odooId, isNew, err := shopifyodoo.ShopifyCustomerToOdoo(customerId.(string), qfn.WebhookTopic(request))
 	if err != nil {
 		return qfn.NetlifyLogAndResponse(500, "Error processing customer", err)
 	}
@@ -23,7 +23,7 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (*event
 		return qfn.NetlifyLogAndResponse(400, "Order Admin API ID not in request body", nil)
 	}
 
-	odooId, isNew, err := shopifyodoo.ShopifyOrderToOdoo(orderId.(string))
+	odooId, isNew, err := shopifyodoo.ShopifyOrderToOdoo(orderId.(string), qfn.WebhookTopic(request))
 	if err != nil {
 		return qfn.NetlifyLogAndResponse(500, "Error processing order", err)
 	}
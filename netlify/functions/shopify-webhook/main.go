@@ -24,10 +24,12 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (*event
 		}, nil
 	}
 
+	topic := request.Headers["x-shopify-topic"]
 	err = rabbitmq.PublishMessage(
 		"shopify.webhook",
-		strings.ReplaceAll(request.Headers["x-shopify-topic"], "/", "."),
+		strings.ReplaceAll(topic, "/", "."),
 		request.Body,
+		map[string]any{qfn.WebhookTopicHeader: topic},
 	)
 	if err != nil {
 		errMsg := "Error! Could not publish message to RabbitMQ"